@@ -1,15 +1,91 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// ListenAddr is the address the HTTP server binds to, set by LoadConfig
+var ListenAddr = ":8080"
+
 // LoadConfig loads environment variables from the .env file
 func LoadConfig() {
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found")
 	}
+
+	addr, err := ParseListenAddr(os.Getenv("HOST"), os.Getenv("PORT"), os.Getenv("LISTEN_ADDR"))
+	if err != nil {
+		log.Fatalf("Invalid listen address: %v", err)
+	}
+	ListenAddr = addr
+}
+
+// ParseListenAddr builds the bind address. LISTEN_ADDR wins when set, otherwise
+// HOST and PORT are combined, with the port defaulting to 8080.
+func ParseListenAddr(host, port, listenAddr string) (string, error) {
+	if listenAddr != "" {
+		_, listenPort, err := net.SplitHostPort(listenAddr)
+		if err != nil {
+			return "", fmt.Errorf("LISTEN_ADDR %q: %w", listenAddr, err)
+		}
+		port = listenPort
+	} else if port == "" {
+		port = "8080"
+	}
+
+	portNumber, err := strconv.Atoi(port)
+	if err != nil || portNumber < 0 || portNumber > 65535 {
+		return "", fmt.Errorf("port %q is not a valid number", port)
+	}
+
+	if listenAddr != "" {
+		return listenAddr, nil
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// GetEnv returns the value of an environment variable or the fallback if it's unset
+func GetEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// GetEnvInt returns an integer environment variable or the fallback if it's unset or invalid
+func GetEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using %d", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// GetEnvDuration returns a duration environment variable (e.g. "10m") or the fallback if it's unset or invalid
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using %s", key, value, fallback)
+		return fallback
+	}
+	return parsed
 }
@@ -0,0 +1,35 @@
+package config
+
+import "testing"
+
+func TestParseListenAddr(t *testing.T) {
+	tests := []struct {
+		host, port, listenAddr string
+		want                   string
+	}{
+		{"", "", "", ":8080"},
+		{"0.0.0.0", "3000", "", "0.0.0.0:3000"},
+		{"", "3000", "127.0.0.1:9000", "127.0.0.1:9000"},
+	}
+	for _, tt := range tests {
+		got, err := ParseListenAddr(tt.host, tt.port, tt.listenAddr)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseListenAddr(%q, %q, %q) = %q, %v; want %q", tt.host, tt.port, tt.listenAddr, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseListenAddrRejectsAnInvalidPort(t *testing.T) {
+	tests := []struct{ host, port, listenAddr string }{
+		{"", "http", ""},
+		{"", "70000", ""},
+		{"", "-1", ""},
+		{"", "", "localhost:http"},
+		{"", "", "no-port"},
+	}
+	for _, tt := range tests {
+		if got, err := ParseListenAddr(tt.host, tt.port, tt.listenAddr); err == nil {
+			t.Errorf("ParseListenAddr(%q, %q, %q) = %q, want an error", tt.host, tt.port, tt.listenAddr, got)
+		}
+	}
+}
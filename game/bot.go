@@ -0,0 +1,189 @@
+package game
+
+import (
+	"sort"
+)
+
+// Bot difficulty levels
+const (
+	BotEasy   = "easy"   // Plays a random legal card
+	BotNormal = "normal" // Leads high, otherwise ducks with the lowest card that can't win
+	BotHard   = "hard"   // Counts played cards and uses trump only when it matters
+)
+
+// BotStrategy picks the card a bot plays from its hand on its turn
+type BotStrategy interface {
+	ChooseCard(game *Game, hand []Card) Card
+}
+
+// StrategyFor returns the strategy for a bot level, defaulting to BotNormal
+func StrategyFor(level string) BotStrategy {
+	switch level {
+	case BotEasy:
+		return EasyBot{}
+	case BotHard:
+		return HardBot{}
+	default:
+		return NormalBot{}
+	}
+}
+
+type EasyBot struct{}
+
+func (EasyBot) ChooseCard(game *Game, hand []Card) Card {
+	legal := game.LegalCards(hand)
+	return legal[Random.Intn(len(legal))]
+}
+
+type NormalBot struct{}
+
+func (NormalBot) ChooseCard(game *Game, hand []Card) Card {
+	legal := sortByValue(game.LegalCards(hand))
+
+	// Lead with the strongest card
+	if len(game.CurrentTrick) == 0 {
+		return legal[len(legal)-1]
+	}
+
+	// Otherwise throw the lowest card that doesn't take the trick
+	for _, c := range legal {
+		if !game.wouldWin(c) {
+			return c
+		}
+	}
+	return legal[0]
+}
+
+type HardBot struct{}
+
+func (HardBot) ChooseCard(game *Game, hand []Card) Card {
+	legal := sortByValue(game.LegalCards(hand))
+	played := game.playedThisRound()
+
+	if len(game.CurrentTrick) == 0 {
+		// Lead a non-trump card that no unplayed card of its suit can beat
+		for i := len(legal) - 1; i >= 0; i-- {
+			c := legal[i]
+			if c.Suit != game.TrumpSuit && isHighestRemaining(c, hand, played, topValue(game.CardOrder)) {
+				return c
+			}
+		}
+		// Otherwise keep the strong cards and trumps for later
+		for _, c := range legal {
+			if c.Suit != game.TrumpSuit {
+				return c
+			}
+		}
+		return legal[0]
+	}
+
+	// Don't fight the partner for a trick they're already taking
+	if game.partnerIsWinning() {
+		return lowestPreferNonTrump(legal, game.TrumpSuit)
+	}
+
+	// Win as cheaply as possible, using trump only when nothing else wins
+	for _, c := range legal {
+		if c.Suit != game.TrumpSuit && game.wouldWin(c) {
+			return c
+		}
+	}
+	for _, c := range legal {
+		if c.Suit == game.TrumpSuit && game.wouldWin(c) {
+			return c
+		}
+	}
+	return lowestPreferNonTrump(legal, game.TrumpSuit)
+}
+
+// wouldWin reports whether playing card now would take the lead in the current trick
+func (g *Game) wouldWin(card Card) bool {
+	if len(g.CurrentTrick) == 0 {
+		return true
+	}
+	return g.beats(card, g.CurrentTrick[g.winningIndex()], g.CurrentTrick[0].Suit)
+}
+
+// partnerIsWinning reports whether the current player's teammate holds the trick
+func (g *Game) partnerIsWinning() bool {
+	if len(g.CurrentTrick) == 0 || len(g.TrickPlayOrder) != len(g.CurrentTrick) {
+		return false
+	}
+	current := g.CurrentPlayer()
+	if current == nil {
+		return false
+	}
+	winner := g.TrickPlayOrder[g.winningIndex()]
+	return winner.ID != current.ID && winner.Team == current.Team
+}
+
+// playedThisRound returns the cards played since trump was last chosen
+func (g *Game) playedThisRound() map[Card]bool {
+	played := make(map[Card]bool)
+	for _, move := range g.Moves {
+		switch move.Action {
+		case "choose_trump":
+			played = make(map[Card]bool)
+		case "play_card":
+			if move.Card != nil {
+				played[*move.Card] = true
+			}
+		}
+	}
+	return played
+}
+
+// isHighestRemaining reports whether no card outside the hand and not yet played outranks
+// card in its suit, top being the Value of the highest rank
+func isHighestRemaining(card Card, hand []Card, played map[Card]bool, top int) bool {
+	held := make(map[Card]bool)
+	for _, c := range hand {
+		held[c] = true
+	}
+	for value := card.Value + 1; value <= top; value++ {
+		higher := false
+		for c := range played {
+			if c.Suit == card.Suit && c.Value == value {
+				higher = true
+			}
+		}
+		for c := range held {
+			if c.Suit == card.Suit && c.Value == value {
+				higher = true
+			}
+		}
+		if !higher {
+			return false
+		}
+	}
+	return true
+}
+
+// topValue returns the Value of the highest rank in the card order
+func topValue(order string) int {
+	top := 0
+	for _, value := range rankValues(order) {
+		if value > top {
+			top = value
+		}
+	}
+	return top
+}
+
+func lowestPreferNonTrump(sorted []Card, trumpSuit string) Card {
+	for _, c := range sorted {
+		if c.Suit != trumpSuit {
+			return c
+		}
+	}
+	return sorted[0]
+}
+
+// sortByValue returns a copy of the cards ordered from lowest to highest value
+func sortByValue(cards []Card) []Card {
+	sorted := append([]Card{}, cards...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value < sorted[j].Value
+	})
+	return sorted
+}
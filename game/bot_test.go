@@ -0,0 +1,110 @@
+package game
+
+import "testing"
+
+// botGame is a seated 2v2 game with spades as trump and seat at the turn, after the
+// cards of trick were played by the seats before it
+func botGame(t *testing.T, order string, seat int, trick ...Card) *Game {
+	t.Helper()
+	g := seatedGame(RoomSettings{TableSize: 4, CardOrder: order})
+	g.TrumpSuit = "spades"
+	first := seat - len(trick)
+	for i, c := range trick {
+		g.CurrentTrick = append(g.CurrentTrick, c)
+		g.TrickPlayOrder = append(g.TrickPlayOrder, g.Players[(first+i+4)%4])
+	}
+	g.CurrentPlayerID = g.Players[seat].ID
+	return g
+}
+
+// cards builds cards of the order from "rank suit" pairs
+func cards(t *testing.T, order string, specs ...[2]string) []Card {
+	t.Helper()
+	var out []Card
+	for _, spec := range specs {
+		c, err := NewOrderedCard(spec[1], spec[0], order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func TestEasyBotFollowsSuit(t *testing.T) {
+	defer func(r RNG) { Random = r }(Random)
+	Random = NewRNG(1)
+
+	lead := cards(t, CardOrderAceHigh, [2]string{"5", "hearts"})
+	hand := cards(t, CardOrderAceHigh, [2]string{"2", "hearts"}, [2]string{"A", "spades"}, [2]string{"K", "clubs"})
+	g := botGame(t, CardOrderAceHigh, 1, lead...)
+
+	for i := 0; i < 20; i++ {
+		if got := (EasyBot{}).ChooseCard(g, hand); got != hand[0] {
+			t.Fatalf("EasyBot played %v holding %v, only the 2 of hearts follows suit", got, hand[0])
+		}
+	}
+}
+
+func TestNormalBotLeadsItsHighestCard(t *testing.T) {
+	hand := cards(t, CardOrderAceHigh, [2]string{"3", "hearts"}, [2]string{"K", "clubs"}, [2]string{"9", "diamonds"})
+	g := botGame(t, CardOrderAceHigh, 0)
+
+	if got := (NormalBot{}).ChooseCard(g, hand); got != hand[1] {
+		t.Fatalf("NormalBot led %v, want %v", got, hand[1])
+	}
+}
+
+func TestNormalBotDucksWithTheLowestLosingCard(t *testing.T) {
+	lead := cards(t, CardOrderAceHigh, [2]string{"Q", "hearts"})
+	hand := cards(t, CardOrderAceHigh, [2]string{"K", "hearts"}, [2]string{"4", "hearts"}, [2]string{"A", "hearts"})
+	g := botGame(t, CardOrderAceHigh, 1, lead...)
+
+	if got := (NormalBot{}).ChooseCard(g, hand); got != hand[1] {
+		t.Fatalf("NormalBot played %v, want %v", got, hand[1])
+	}
+}
+
+func TestHardBotLetsItsPartnerTakeTheTrick(t *testing.T) {
+	trick := cards(t, CardOrderAceHigh, [2]string{"A", "hearts"}, [2]string{"3", "hearts"})
+	hand := cards(t, CardOrderAceHigh, [2]string{"10", "hearts"}, [2]string{"5", "hearts"}, [2]string{"2", "spades"})
+	g := botGame(t, CardOrderAceHigh, 2, trick...) // Seat 0, the partner, leads the Ace
+
+	if got := (HardBot{}).ChooseCard(g, hand); got != hand[1] {
+		t.Fatalf("HardBot played %v under its partner's Ace, want %v", got, hand[1])
+	}
+}
+
+func TestHardBotTrumpsWithItsLowestWinningTrump(t *testing.T) {
+	lead := cards(t, CardOrderAceHigh, [2]string{"K", "clubs"})
+	hand := cards(t, CardOrderAceHigh, [2]string{"9", "spades"}, [2]string{"3", "spades"}, [2]string{"2", "hearts"})
+	g := botGame(t, CardOrderAceHigh, 1, lead...)
+
+	if got := (HardBot{}).ChooseCard(g, hand); got != hand[1] {
+		t.Fatalf("HardBot played %v, want the lowest trump %v", got, hand[1])
+	}
+}
+
+func TestHardBotLeadsACardNothingLeftCanBeat(t *testing.T) {
+	hand := cards(t, CardOrderAceHigh, [2]string{"K", "hearts"}, [2]string{"4", "hearts"}, [2]string{"7", "clubs"})
+
+	g := botGame(t, CardOrderAceHigh, 0)
+	if got := (HardBot{}).ChooseCard(g, hand); got != hand[1] {
+		t.Fatalf("HardBot led %v with the Ace of hearts still out, want the low %v", got, hand[1])
+	}
+
+	ace := cards(t, CardOrderAceHigh, [2]string{"A", "hearts"})[0]
+	g.Moves = append(g.Moves, MoveRecord{Action: "play_card", PlayerID: g.Players[3].ID, Card: &ace})
+	if got := (HardBot{}).ChooseCard(g, hand); got != hand[0] {
+		t.Fatalf("HardBot led %v once the Ace of hearts was played, want %v", got, hand[0])
+	}
+}
+
+func TestHardBotKnowsTheKingIsTopWithAceLow(t *testing.T) {
+	hand := cards(t, CardOrderAceLow, [2]string{"K", "hearts"}, [2]string{"4", "clubs"})
+	g := botGame(t, CardOrderAceLow, 0)
+
+	if got := (HardBot{}).ChooseCard(g, hand); got != hand[0] {
+		t.Fatalf("HardBot led %v, want %v, the top of hearts when the Ace is low", got, hand[0])
+	}
+}
@@ -0,0 +1,71 @@
+package game
+
+import "fmt"
+
+// Suits lists the four suits of the deck
+var Suits = []string{"hearts", "diamonds", "clubs", "spades"}
+
+// Ranks lists the ranks of a suit, lowest first
+var Ranks = []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "A"}
+
+// How ranks are ordered within a suit, chosen per room through CARD_ORDER
+const (
+	CardOrderAceHigh = "ace_high" // The Ace beats the King
+	CardOrderAceLow  = "ace_low"  // The Ace loses to the 2
+)
+
+// CardOrders maps each card order to the Value of every rank. Cards compare by
+// Value, so the order only decides these numbers.
+var CardOrders = map[string]map[string]int{
+	CardOrderAceHigh: {
+		"2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9, "10": 10,
+		"J": 11, "Q": 12, "K": 13, "A": 14,
+	},
+	CardOrderAceLow: {
+		"A": 1, "2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9, "10": 10,
+		"J": 11, "Q": 12, "K": 13,
+	},
+}
+
+// rankValues returns the values of the card order, Ace high for an unknown order
+func rankValues(order string) map[string]int {
+	if values, ok := CardOrders[order]; ok {
+		return values
+	}
+	return CardOrders[CardOrderAceHigh]
+}
+
+// NewCard returns the card of the suit and rank with its Value set from the rank,
+// Ace high. Every card should be built with it or NewOrderedCard so Value always
+// matches Rank.
+func NewCard(suit, rank string) (Card, error) {
+	return NewOrderedCard(suit, rank, CardOrderAceHigh)
+}
+
+// NewOrderedCard is NewCard for a room's card order
+func NewOrderedCard(suit, rank, order string) (Card, error) {
+	if !IsValidSuit(suit) {
+		return Card{}, fmt.Errorf("invalid suit %q", suit)
+	}
+	value, ok := rankValues(order)[rank]
+	if !ok {
+		return Card{}, fmt.Errorf("invalid rank %q", rank)
+	}
+	return Card{Suit: suit, Rank: rank, Value: value}, nil
+}
+
+// IsValidSuit reports whether suit is one of Suits
+func IsValidSuit(suit string) bool {
+	for _, s := range Suits {
+		if s == suit {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidRank reports whether rank is one of Ranks
+func IsValidRank(rank string) bool {
+	_, ok := rankValues(CardOrderAceHigh)[rank]
+	return ok
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCardJSONRoundTrip(t *testing.T) {
+	queen := cards(t, CardOrderAceHigh, [2]string{"Q", "hearts"})[0]
+
+	data, err := json.Marshal(queen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"suit":"hearts","rank":"Q","value":12}`; string(data) != want {
+		t.Fatalf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	// The capitalized keys of older clients still decode
+	for _, in := range []string{string(data), `{"Suit":"hearts","Rank":"Q","Value":12}`} {
+		var got Card
+		if err := json.Unmarshal([]byte(in), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != queen {
+			t.Errorf("json.Unmarshal(%s) = %+v, want %+v", in, got, queen)
+		}
+	}
+}
+
+func TestNewCardSetsTheValueOfTheRank(t *testing.T) {
+	for _, suit := range Suits {
+		for i, rank := range Ranks {
+			c, err := NewCard(suit, rank)
+			if err != nil {
+				t.Fatalf("NewCard(%s, %s) error = %v", suit, rank, err)
+			}
+			if want := (Card{Suit: suit, Rank: rank, Value: i + 2}); c != want {
+				t.Errorf("NewCard(%s, %s) = %+v, want %+v", suit, rank, c, want)
+			}
+		}
+	}
+}
+
+func TestNewCardRejectsAnInvalidSuitOrRank(t *testing.T) {
+	tests := []struct {
+		suit, rank string
+	}{
+		{"stars", "A"},
+		{"Hearts", "A"},
+		{"", "A"},
+		{"hearts", "1"},
+		{"hearts", "14"},
+		{"hearts", "a"},
+		{"hearts", ""},
+	}
+	for _, tt := range tests {
+		if c, err := NewCard(tt.suit, tt.rank); err == nil {
+			t.Errorf("NewCard(%q, %q) = %+v, want an error", tt.suit, tt.rank, c)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package game
+
+import "testing"
+
+func TestCloneIsIndependentOfTheGame(t *testing.T) {
+	trick := cards(t, CardOrderAceHigh, [2]string{"K", "hearts"})
+	g := botGame(t, CardOrderAceHigh, 1, trick...)
+	g.TrumpPlayer = g.Players[0]
+	g.Players[1].Hand = cards(t, CardOrderAceHigh, [2]string{"2", "hearts"}, [2]string{"A", "clubs"})
+	g.Scores["team1"] = 3
+
+	clone := g.Clone()
+	clone.Players[1].Hand[0] = trick[0]
+	clone.Players[1].Hand = clone.Players[1].Hand[:1]
+	clone.CurrentTrick[0] = clone.Players[1].Hand[0]
+	clone.Scores["team1"] = 6
+	clone.TrumpPlayer.Team = "team1"
+
+	if hand := g.Players[1].Hand; len(hand) != 2 || hand[0].Rank != "2" {
+		t.Errorf("hand after changing the clone = %v, want it untouched", hand)
+	}
+	if g.Scores["team1"] != 3 {
+		t.Errorf("score after changing the clone = %d, want 3", g.Scores["team1"])
+	}
+	if g.TrumpPlayer.Team != "team2" {
+		t.Errorf("Trump Player moved to %s with the clone", g.TrumpPlayer.Team)
+	}
+	if clone.TrumpPlayer != clone.Players[0] || clone.TrickPlayOrder[0] != clone.Players[0] {
+		t.Error("the clone's Trump Player and play order don't point at its own players")
+	}
+}
@@ -0,0 +1,172 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"hokm-backend/config"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultSendQueueSize is how many messages may wait for a slow client before it's
+// dropped, unless SEND_QUEUE_SIZE says otherwise
+const DefaultSendQueueSize = 256
+
+// DefaultWriteTimeout bounds a single write to a client, unless WRITE_TIMEOUT says otherwise
+const DefaultWriteTimeout = 10 * time.Second
+
+var (
+	ErrConnClosed     = errors.New("connection is closed")
+	ErrSendQueueFull  = errors.New("send queue is full")
+	errCloseRequested = errors.New("close requested")
+)
+
+// Conn wraps a WebSocket connection with its own send queue. Writes only encode and
+// enqueue the message, so a broadcast (often made under Manager.Mu) never waits on a
+// slow client; a writer goroutine per connection drains the queue to the socket in
+// order. A message is encoded before it's queued, as its payload may share maps and
+// slices with the game that change once the lock is released. A client that falls
+// SEND_QUEUE_SIZE messages behind is disconnected.
+type Conn struct {
+	*websocket.Conn
+	sendMu    sync.Mutex // Keeps numbering and queueing in the same order
+	events    *EventLog  // Numbers the messages sent through this connection, if it's in a room
+	eventsTo  string     // Recipient the messages are logged for: the seat's player or the observer
+	queue     chan outbound
+	done      chan struct{}
+	closeOnce sync.Once
+	timeout   time.Duration
+}
+
+// outbound is an encoded message, or with closeFrame set, the request to close after
+// everything queued before it was written
+type outbound struct {
+	data       []byte
+	closeFrame []byte
+}
+
+// NewConn wraps ws and starts its writer goroutine, which runs until the connection closes
+func NewConn(ws *websocket.Conn) *Conn {
+	size := config.GetEnvInt("SEND_QUEUE_SIZE", DefaultSendQueueSize)
+	if size < 1 {
+		size = DefaultSendQueueSize
+	}
+	c := &Conn{
+		Conn:    ws,
+		queue:   make(chan outbound, size),
+		done:    make(chan struct{}),
+		timeout: config.GetEnvDuration("WRITE_TIMEOUT", DefaultWriteTimeout),
+	}
+	go c.writeLoop()
+	return c
+}
+
+// SetEvents numbers what's sent from now on with the room's EventLog, logged for the recipient
+func (c *Conn) SetEvents(events *EventLog, to string) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	c.events, c.eventsTo = events, to
+}
+
+// WriteJSON queues v for the client without blocking. Responses are numbered by the
+// room's EventLog once SetEvents was called. A full queue drops the client.
+func (c *Conn) WriteJSON(v interface{}) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if resp, ok := v.(WSResponse); ok && c.events != nil {
+		data, err := c.events.Stamp(c.eventsTo, resp)
+		if err != nil {
+			return err
+		}
+		return c.enqueue(outbound{data: data})
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.enqueue(outbound{data: data})
+}
+
+// Replay resends encoded messages from the EventLog with their original Seq
+func (c *Conn) Replay(events [][]byte) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	for _, data := range events {
+		if err := c.enqueue(outbound{data: data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CloseWith closes the connection with a close frame once the messages already
+// queued have been written
+func (c *Conn) CloseWith(code int, reason string) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if c.enqueue(outbound{closeFrame: websocket.FormatCloseMessage(code, reason)}) != nil {
+		c.Close()
+	}
+}
+
+// Close stops the writer and closes the socket right away, dropping anything still queued
+func (c *Conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.Conn.Close()
+	})
+	return err
+}
+
+// enqueue adds an item to the queue without blocking. The caller must hold sendMu.
+func (c *Conn) enqueue(item outbound) error {
+	select {
+	case <-c.done:
+		return ErrConnClosed
+	default:
+	}
+
+	select {
+	case c.queue <- item:
+		return nil
+	default:
+		log.Printf("🐢 Send queue of %s is full, dropping the connection", c.RemoteAddr())
+		c.Close()
+		return ErrSendQueueFull
+	}
+}
+
+// writeLoop is the only writer of the socket's data frames
+func (c *Conn) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case item := <-c.queue:
+			if err := c.write(item); err != nil {
+				if !errors.Is(err, errCloseRequested) {
+					log.Printf("Write to %s failed: %v", c.RemoteAddr(), err)
+				}
+				c.Close()
+				return
+			}
+		}
+	}
+}
+
+func (c *Conn) write(item outbound) error {
+	deadline := time.Now().Add(c.timeout)
+	if item.closeFrame != nil {
+		c.Conn.WriteControl(websocket.CloseMessage, item.closeFrame, deadline)
+		return errCloseRequested
+	}
+	if c.timeout > 0 {
+		c.Conn.SetWriteDeadline(deadline)
+	}
+	return c.Conn.WriteMessage(websocket.TextMessage, item.data)
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// connPair serves one WebSocket connection and returns the server's Conn for it with
+// the client end
+func connPair(t *testing.T) (*Conn, *websocket.Conn) {
+	t.Helper()
+	conns := make(chan *Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conns <- NewConn(ws)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	conn := <-conns
+	t.Cleanup(func() { conn.Close() })
+	return conn, client
+}
+
+func TestAStalledReaderDoesntHoldUpOtherConnections(t *testing.T) {
+	reader, readerClient := connPair(t)
+	// The stalled client never reads, so its writer soon blocks on the socket
+	stalled, _ := connPair(t)
+
+	// Queueing returns without waiting on the socket, however far behind the client is
+	big := strings.Repeat("x", 256<<10)
+	queued := make(chan error, 1)
+	go func() {
+		for i := 0; i < DefaultSendQueueSize/2; i++ {
+			if err := stalled.WriteJSON(WSResponse{Type: "game_update", Payload: big}); err != nil {
+				queued <- err
+				return
+			}
+		}
+		queued <- nil
+	}()
+	select {
+	case err := <-queued:
+		if err != nil {
+			t.Fatalf("write to the stalled client: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("writing to the stalled client blocked")
+	}
+
+	// Everyone else is still written to
+	for i := 0; i < 10; i++ {
+		if err := reader.WriteJSON(WSResponse{Type: "turn_update", Payload: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readerClient.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for i := 0; i < 10; i++ {
+		var resp struct {
+			Type    string `json:"type"`
+			Payload int    `json:"payload"`
+		}
+		if err := readerClient.ReadJSON(&resp); err != nil {
+			t.Fatalf("message %d never reached the reader: %v", i, err)
+		}
+		if resp.Payload != i {
+			t.Fatalf("message %d arrived as %d, want them in order", i, resp.Payload)
+		}
+	}
+
+	// Falling a whole queue behind drops the stalled client
+	var err error
+	for i := 0; i <= DefaultSendQueueSize && err == nil; i++ {
+		err = stalled.WriteJSON(WSResponse{Type: "game_update", Payload: big})
+	}
+	if !errors.Is(err, ErrSendQueueFull) {
+		t.Fatalf("overflowing the queue returned %v, want %v", err, ErrSendQueueFull)
+	}
+	if err := stalled.WriteJSON(WSResponse{Type: "game_update"}); !errors.Is(err, ErrConnClosed) {
+		t.Fatalf("writing to the dropped client returned %v, want %v", err, ErrConnClosed)
+	}
+}
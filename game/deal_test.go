@@ -0,0 +1,58 @@
+package game
+
+import "testing"
+
+// dealtGame is a seated 2v2 game with the first batch of 5 dealt to every seat and
+// the rest of a full deck left undealt. It returns the expected hand sizes.
+func dealtGame(t *testing.T) (*Game, map[string]int) {
+	t.Helper()
+	g := seatedGame(RoomSettings{TableSize: 4})
+	var deck []Card
+	for _, suit := range Suits {
+		for _, rank := range Ranks {
+			c, err := NewCard(suit, rank)
+			if err != nil {
+				t.Fatal(err)
+			}
+			deck = append(deck, c)
+		}
+	}
+	expected := make(map[string]int)
+	for _, p := range g.Players {
+		p.Hand, deck = deck[:5], deck[5:]
+		expected[p.ID] = 5
+	}
+	g.Deck = deck
+	return g, expected
+}
+
+func TestValidateDeal(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(g *Game)
+		wantErr bool
+	}{
+		{"valid", func(*Game) {}, false},
+		{"card dealt twice", func(g *Game) {
+			g.Players[1].Hand[0] = g.Players[0].Hand[0]
+		}, true},
+		{"card in a hand and the deck", func(g *Game) {
+			g.Deck[0] = g.Players[2].Hand[4]
+		}, true},
+		{"short hand", func(g *Game) {
+			g.Players[3].Hand = g.Players[3].Hand[:4]
+		}, true},
+		{"card lost", func(g *Game) {
+			g.Deck = g.Deck[1:]
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, expected := dealtGame(t)
+			tt.corrupt(g)
+			if err := g.ValidateDeal(expected, 52); (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateDeal() = %v, want an error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// EventLogSize is how many recent messages of a room are kept for replay
+const EventLogSize = 256
+
+// EventLog numbers the messages sent in one room and keeps the most recent ones. The
+// sequence is shared by every seat and observer of the room; each message also carries
+// the Seq of the previous message to the same recipient, so a client can spot a gap
+// and ask for what it missed after reconnecting. The log belongs to the Room, so it
+// outlives the connections.
+type EventLog struct {
+	mu     sync.Mutex
+	seq    uint64
+	last   map[string]uint64 // Recipient -> Seq of the last message sent to them
+	recent []event           // Ring buffer of the last EventLogSize messages
+}
+
+// event is an encoded message and who it was sent to
+type event struct {
+	seq  uint64
+	to   string
+	data []byte
+}
+
+func NewEventLog() *EventLog {
+	return &EventLog{
+		last:   make(map[string]uint64),
+		recent: make([]event, 0, EventLogSize),
+	}
+}
+
+// Stamp gives the message for the recipient the room's next sequence number, then
+// encodes and remembers it
+func (l *EventLog) Stamp(to string, resp WSResponse) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	resp.Seq = l.seq + 1
+	resp.PrevSeq = l.last[to]
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	l.seq++
+	l.last[to] = l.seq
+	e := event{seq: l.seq, to: to, data: data}
+	if len(l.recent) < EventLogSize {
+		l.recent = append(l.recent, e)
+	} else {
+		l.recent[(l.seq-1)%EventLogSize] = e
+	}
+	return data, nil
+}
+
+// Since returns the kept messages to the recipient numbered after seq, oldest first.
+// ok is false when messages after seq were already dropped from the log, as some of
+// them may have been the recipient's.
+func (l *EventLog) Since(to string, seq uint64) (events [][]byte, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if seq >= l.seq {
+		return nil, true
+	}
+	oldest := uint64(1)
+	if l.seq > EventLogSize {
+		oldest = l.seq - EventLogSize + 1
+	}
+	if seq+1 < oldest {
+		return nil, false
+	}
+	for s := seq + 1; s <= l.seq; s++ {
+		if e := l.recent[(s-1)%EventLogSize]; e.to == to {
+			events = append(events, e.data)
+		}
+	}
+	return events, true
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func stamp(t *testing.T, l *EventLog, to string) WSResponse {
+	t.Helper()
+	data, err := l.Stamp(to, WSResponse{Type: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp WSResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestEventLogNumbersTheRoomAndChainsEachRecipient(t *testing.T) {
+	l := NewEventLog()
+
+	a1 := stamp(t, l, "a")
+	b1 := stamp(t, l, "b")
+	a2 := stamp(t, l, "a")
+	if a1.Seq != 1 || b1.Seq != 2 || a2.Seq != 3 {
+		t.Fatalf("Seq = %d, %d, %d; want 1, 2, 3 shared by the room", a1.Seq, b1.Seq, a2.Seq)
+	}
+	if a1.PrevSeq != 0 || b1.PrevSeq != 0 || a2.PrevSeq != a1.Seq {
+		t.Fatalf("PrevSeq = %d, %d, %d; want 0, 0, %d", a1.PrevSeq, b1.PrevSeq, a2.PrevSeq, a1.Seq)
+	}
+
+	// A client that got a1 but not a2 sees the gap on the next message
+	a3 := stamp(t, l, "a")
+	if lastSeen := a1.Seq; a3.PrevSeq == lastSeen {
+		t.Fatal("no gap detected after a message to a was lost")
+	}
+
+	events, ok := l.Since("a", a1.Seq)
+	if !ok || len(events) != 2 {
+		t.Fatalf("Since(a, %d) = %d events, %v; want a2 and a3", a1.Seq, len(events), ok)
+	}
+}
+
+func TestEventLogReportsMessagesThatAreNoLongerKept(t *testing.T) {
+	l := NewEventLog()
+	first := stamp(t, l, "a")
+	for i := 0; i < EventLogSize; i++ {
+		stamp(t, l, "b")
+	}
+
+	if _, ok := l.Since("a", first.Seq-1); ok {
+		t.Fatal("Since() = ok after the message was dropped from the ring buffer")
+	}
+	if events, ok := l.Since("a", first.Seq); !ok || len(events) != 0 {
+		t.Fatalf("Since(a, %d) = %d events, %v; want none missed", first.Seq, len(events), ok)
+	}
+}
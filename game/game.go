@@ -1,64 +1,363 @@
 package game
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
+	"hokm-backend/config"
+	"log"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/gorilla/websocket"
 	"gorm.io/gorm"
 )
 
+const TargetScore = 7 // Rounds a team needs to take the match
+
+// ErrNotYourTurn is returned by PlayCard when someone other than the current player plays
+var ErrNotYourTurn = errors.New("it's not your turn")
+
+// ErrDuplicateMove is returned by PlayMove for a move_id that was already played
+var ErrDuplicateMove = errors.New("move already applied")
+
+// UndoWindow is how long a player has to take back the card they just played
+const UndoWindow = 3 * time.Second
+
+// What happens when a disconnected player doesn't come back within the room's ReconnectTimeout
+const (
+	DisconnectWaitReplacement = "wait_replacement" // Drop the player and wait for someone to take the seat
+	DisconnectForfeit         = "forfeit"          // The player's team forfeits the match
+)
+
+// What happens to an unfinished trick when a player leaves mid-trick
+const (
+	TrickKeep    = "keep"    // The cards stay on the table and the replacement plays on
+	TrickDiscard = "discard" // The cards go back to the hands that played them and the trick restarts
+)
+
+// How the first Trump Player of a match is picked
+const (
+	TrumpSelectionAce     = "ace"          // Cards are dealt round the table until someone gets an Ace
+	TrumpSelectionHighest = "highest_card" // Everyone gets a card, the highest wins; tied players draw again
+	TrumpSelectionFixed   = "fixed"        // The player in the first seat, no draw
+)
+
+// Game modes a room can be played in
+const (
+	ModeStandard = "standard" // Trump is chosen from the first 5 cards
+	ModeDark     = "dark"     // Trump is chosen blind, then full hands are dealt
+)
+
+// Deck variants a room can be played with
+const (
+	DeckStandard = "standard" // Full 52-card deck, 13 cards per player
+	DeckStripped = "stripped" // 32-card deck without the 2-6, 8 cards per player
+)
+
 type GameHistory struct {
 	gorm.Model
-	Players []string `gorm:"type:text[]"`
-	Winner  string
-	Score   int
+	Players      []string `gorm:"serializer:json"` // Stored as JSON so every DB_DRIVER can hold it
+	Winner       string
+	Score        int
+	PlayerTricks map[string]int `gorm:"serializer:json"` // Tricks taken by each player over the match
+	Moves        []MoveRecord   `gorm:"serializer:json"` // Every move of the match, in order
+	Scoring      ScoringRules   `gorm:"serializer:json"` // Rules the match was scored by; zero on rows saved before they were kept
+	Seats        []GameSeat     // Result of the match for each account that played it
+}
+
+// GameSeat is one account's result in a finished match, used for per-user stats
+type GameSeat struct {
+	ID            uint   `gorm:"primarykey"`
+	GameHistoryID uint   `gorm:"index"`
+	UserID        string `gorm:"index"`
+	Seat          *int   // Position of the account's name in GameHistory.Players; nil on rows saved before it was kept
+	Team          string
+	Won           bool
+	Kots          int // Kot and Trump-Kot Rounds the seat's team won
+}
+
+// MoveRecord is one entry of a game's replay log
+type MoveRecord struct {
+	Action    string    `json:"action"` // "play_card", "choose_trump", "renege_detected" or "forfeit"
+	PlayerID  string    `json:"player_id"`
+	Team      string    `json:"team,omitempty"` // Team of the player, used to replay the scores
+	Card      *Card     `json:"card,omitempty"`
+	TrumpSuit string    `json:"trump_suit,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PeekWindow is how long after a trick completes its cards can be reviewed
+const PeekWindow = 5 * time.Second
+
+// MaxRoundTricks bounds the trick history kept for a Round (13 cards per hand)
+const MaxRoundTricks = 13
+
+// TrickRecord is a finished trick of the current Round
+type TrickRecord struct {
+	Cards     []Card   `json:"cards"`      // In play order
+	PlayerIDs []string `json:"player_ids"` // Who played each card
+	WinnerID  string   `json:"winner_id"`
 }
 
 type Game struct {
-	Deck               []Card
-	TrumpSuit          string
-	Players            []*Player
-	CurrentTrick       []Card
-	TrickPlayOrder     []*Player
-	Scores             map[string]int // Scores for the current Round (tricks won)
-	RoundScores        map[string]int // Scores for the overall game (Rounds won)
-	CurrentPlayerIndex int
-	DealerIndex        int
-	TrumpPlayer        *Player
-	CurrentRound       int  // Current Round number (1 to 7)
-	IsGameOver         bool // Flag to indicate if the game is over
+	Deck             []Card
+	TrumpSuit        string
+	Players          []*Player
+	CurrentTrick     []Card
+	TrickPlayOrder   []*Player
+	Scores           map[string]int // Scores for the current Round (tricks won)
+	RoundScores      map[string]int // Scores for the overall game (Rounds won)
+	CurrentPlayerID  string         // Player whose turn it is, empty before the first lead
+	DealerIndex      int            // Position in Players of this Round's dealer, one seat on each Round
+	TrumpPlayer      *Player
+	CurrentRound     int             // Current Round number (1 to 7)
+	TricksWon        map[string]int  // Tricks taken by each player in the current Round
+	TotalTricksWon   map[string]int  // Tricks taken by each player over the whole match
+	LastPlayAt       time.Time       // When the last card of the current trick was played
+	Moves            []MoveRecord    // Replay log of the match
+	TricksToWinRound int             // Tricks a team needs to take the Round
+	Phase            Phase           // Where the game is in its lifecycle; changed through Transition
+	PausedFrom       Phase           // The phase a paused game resumes in
+	RoundTricks      []TrickRecord   // Tricks already played this Round, oldest first
+	LastTrickAt      time.Time       // When the last trick of RoundTricks was completed
+	Kots             map[string]int  // Rounds each team won without losing a trick (Kot or Trump-Kot)
+	AppliedMoves     map[string]Card // Cards played this Round by client move_id, see PlayMove
+	CardOrder        string          // The room's card order, which tells the bots the top rank
 }
 
 type Room struct {
-	ID                 string                      // Unique identifier for the room
-	Players            []*Player                   // List of players in the room
-	Game               *Game                       // The game being played in the room
-	SavedPlayers       map[string]*SavedPlayerData // Add this
-	CurrentPlayerIndex int                         // Store the current player index
+	ID             string                      // Unique identifier for the room
+	Players        []*Player                   // List of players in the room
+	Game           *Game                       // The game being played in the room
+	SavedPlayers   map[string]*SavedPlayerData // Add this
+	Settings       RoomSettings                // Rules chosen when the room was created
+	Started        bool                        // Set once dealing has begun for the table
+	CancelDeal     context.CancelFunc          // Stops an in-progress dealing sequence
+	CancelStart    context.CancelFunc          // Stops a running game_starting countdown
+	CancelAdvance  context.CancelFunc          // Stops a pending broadcast of the cleared trick
+	CancelTrump    context.CancelFunc          // Stops the choose_trump timeout
+	CancelTurn     context.CancelFunc          // Stops the turn timer of the player on turn
+	CreatedAt      time.Time                   // When the room was opened
+	BelowFullSince time.Time                   // When the room last had a free seat; zero while it's full
+	Events         *EventLog                   // Numbers the messages sent to the room's seats and observers
+	KickVotes      map[string]map[string]bool  // Target player ID -> IDs of players voting to kick
+	RematchVotes   map[string]bool             // Player ID -> opted in to a rematch
+	SwapRequests   map[string]string           // Player ID -> player they asked to swap seats with
+	Observers      map[string]*Observer        // Spectators and coaches watching without a seat
+}
+
+// Observer watches a room without a seat. Spectators see no hands; coaches see every hand.
+type Observer struct {
+	ID     string
+	UserID string
+	Coach  bool
+	Conn   *Conn
 }
 
+// ObserverList returns the room's observers, for sending to them once Mu is released.
+// The caller must hold Manager.Mu.
+func (r *Room) ObserverList() []*Observer {
+	observers := make([]*Observer, 0, len(r.Observers))
+	for _, o := range r.Observers {
+		observers = append(observers, o)
+	}
+	return observers
+}
+
+// RoomSettings holds the per-room options fixed at room creation
+type RoomSettings struct {
+	DisconnectPolicy string // DisconnectWaitReplacement or DisconnectForfeit
+	DeckVariant      string // DeckStandard or DeckStripped
+	GameMode         string // ModeStandard or ModeDark
+	Scoring          ScoringRules
+	DealPattern      []int         // Cards per dealing batch, e.g. [5 4 4]; see DealBatches
+	TrickOnLeave     string        // TrickKeep or TrickDiscard
+	TableSize        int           // 4 for 2v2 or 6 for 3v3; see Seats
+	TrumpSelection   string        // TrumpSelectionAce, TrumpSelectionHighest or TrumpSelectionFixed
+	ReconnectTimeout time.Duration // How long a disconnected player's seat is held; see ReconnectWait
+	CardOrder        string        // CardOrderAceHigh or CardOrderAceLow
+	BotLevel         string        // BotEasy, BotNormal or BotHard, for turns the timer plays
+}
+
+// Bounds of a room's reconnect timeout, set through RECONNECT_TIMEOUT
+const (
+	DefaultReconnectTimeout = 30 * time.Second
+	MinReconnectTimeout     = 10 * time.Second
+	MaxReconnectTimeout     = 120 * time.Second
+)
+
+// ReconnectWait is how long a disconnected player has to come back, the default for
+// settings that don't set one
+func (s RoomSettings) ReconnectWait() time.Duration {
+	if s.ReconnectTimeout <= 0 {
+		return DefaultReconnectTimeout
+	}
+	return s.ReconnectTimeout
+}
+
+// ScoringRules are the points a Round is worth, depending on how it was won
+type ScoringRules struct {
+	Normal   int `json:"normal"`    // Any win where the losing team took a trick
+	Kot      int `json:"kot"`       // Trump team takes TricksToWinRound tricks without losing one
+	TrumpKot int `json:"trump_kot"` // Opposite team does the same against the Trump team
+}
+
+// DefaultScoringRules are the usual Hokm points: 1, 2 for Kot and 3 for Trump-Kot
+func DefaultScoringRules() ScoringRules {
+	return ScoringRules{Normal: 1, Kot: 2, TrumpKot: 3}
+}
+
+// Validate checks that every kind of win is worth at least a point
+func (r ScoringRules) Validate() error {
+	if r.Normal < 1 || r.Kot < 1 || r.TrumpKot < 1 {
+		return fmt.Errorf("scoring rules must be positive, got %+v", r)
+	}
+	return nil
+}
+
+// RoundPoints returns what a Round is worth for the winner, given the tricks of
+// the losing team and whether the winner is the Trump team
+func (r ScoringRules) RoundPoints(losingScore int, winnerIsTrumpTeam bool) int {
+	switch {
+	case losingScore == 0 && winnerIsTrumpTeam:
+		return r.Kot
+	case losingScore == 0:
+		return r.TrumpKot
+	default:
+		return r.Normal
+	}
+}
+
+// DealBatches returns the number of cards handed out in each dealing batch:
+// the room's DealPattern, or the usual pattern of its deck when none is set.
+// The first batch is the Trump Player's hand used to choose trump.
+func (s RoomSettings) DealBatches() []int {
+	if len(s.DealPattern) > 0 {
+		return s.DealPattern
+	}
+	return DefaultDealPattern(s.CardsPerPlayer())
+}
+
+// DefaultDealPattern is 5-4-4 for 13-card hands and 5-3 for 8-card ones
+func DefaultDealPattern(cardsPerPlayer int) []int {
+	if cardsPerPlayer == 8 {
+		return []int{5, 3}
+	}
+	return []int{5, 4, 4}
+}
+
+// ValidateDealPattern checks that every batch deals cards and that the batches add
+// up to a full hand
+func ValidateDealPattern(pattern []int, cardsPerPlayer int) error {
+	total := 0
+	for _, n := range pattern {
+		if n < 1 {
+			return fmt.Errorf("deal pattern %v has an empty batch", pattern)
+		}
+		total += n
+	}
+	if total != cardsPerPlayer {
+		return fmt.Errorf("deal pattern %v deals %d cards, a hand has %d", pattern, total, cardsPerPlayer)
+	}
+	return nil
+}
+
+// Seats returns the number of players at the table, 4 unless the room is a 3v3 table
+func (s RoomSettings) Seats() int {
+	if s.TableSize == 6 {
+		return 6
+	}
+	return 4
+}
+
+// DeckSize returns the number of cards in the room's deck
+func (s RoomSettings) DeckSize() int {
+	return s.Seats() * s.CardsPerPlayer()
+}
+
+// CardsPerPlayer returns the size of a full hand for the room's deck. A 6-player
+// table plays the standard deck without the 2s, 8 cards each.
+func (s RoomSettings) CardsPerPlayer() int {
+	if s.DeckVariant == DeckStripped || s.Seats() == 6 {
+		return 8
+	}
+	return 13
+}
+
+// TricksToWinRound returns the number of tricks a team needs to take the Round
+func (s RoomSettings) TricksToWinRound() int {
+	return s.CardsPerPlayer()/2 + 1
+}
+
+// GameManager holds every room. Mu guards the rooms and everything in them: the
+// players, their hands and connection state, the game and the rooms' cancel funcs.
+// Anything that changes that state holds Mu.Lock, from the lookup of the room to
+// the last write, and never releases it in between; code that only reads (e.g. a
+// broadcast) may hold Mu.RLock. Functions named ...Locked, and the handlers'
+// cancel and arm helpers, expect the caller to hold Mu already and must not lock it again.
 type GameManager struct {
 	Rooms map[string]*Room
+	Queue []*Player    // Players waiting for a new table, longest waiting first
 	Mu    sync.RWMutex // Capitalize to export the field
 }
 
 type Card struct {
-	Suit  string // e.g., "hearts", "diamonds", "clubs", "spades"
-	Rank  string // e.g., "2", "3", ..., "10", "J", "Q", "K", "A"
-	Value int    // Numeric value for ranking
+	Suit  string `json:"suit"`  // e.g., "hearts", "diamonds", "clubs", "spades"
+	Rank  string `json:"rank"`  // e.g., "2", "3", ..., "10", "J", "Q", "K", "A"
+	Value int    `json:"value"` // Numeric value for ranking
 }
 
 type Player struct {
-	ID        string          `json:"id"`
-	Name      string          `json:"name"`
-	Team      string          `json:"team"`
-	Hand      []Card          `json:"hand,omitempty"`
-	Conn      *websocket.Conn `json:"-"`
-	Connected bool            `json:"connected"` // Add this
-	Index     int             `json:"index"`     // Add this to maintain position
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Team      string `json:"team"`
+	Hand      []Card `json:"hand,omitempty"`
+	Conn      *Conn  `json:"-"`
+	Connected bool   `json:"connected"`           // Add this
+	Index     int    `json:"index"`               // Add this to maintain position
+	Ready     bool   `json:"ready"`               // Player confirmed they're ready for the deal
+	BotLevel  string `json:"bot_level,omitempty"` // BotEasy, BotNormal or BotHard when a bot plays the seat
+	UserID    string `json:"-"`                   // Account of the player, empty for anonymous connections
+	IsGuest   bool   `json:"is_guest"`            // Joined with a POST /guest token; never recorded in stats
+
+	LastReactionAt  time.Time          `json:"-"` // Used to rate-limit reactions
+	LastChatAt      time.Time          `json:"-"` // Used to rate-limit team chat
+	Events          *EventLog          `json:"-"` // EventLog of the player's room, kept across reconnects
+	CancelReconnect context.CancelFunc `json:"-"` // Stops the reconnect countdown once the player is back
+}
+
+// Attach makes conn the player's connection, numbering what it sends with the room's EventLog
+func (p *Player) Attach(conn *Conn) {
+	p.Conn = conn
+	if p.Events != nil {
+		conn.SetEvents(p.Events, p.ID)
+	}
+}
+
+// Send writes resp to the player's connection. A failed write means the connection is
+// broken, so it's closed: the player's read loop then fails as well and runs the usual
+// disconnect handling. Send never takes Manager.Mu, so it's safe to call under the lock.
+func (p *Player) Send(resp WSResponse) error {
+	if p.Conn == nil {
+		return errors.New("player has no connection")
+	}
+	if err := p.Conn.WriteJSON(resp); err != nil {
+		log.Printf("Write to player %s failed, closing the connection: %v", p.ID, err)
+		p.Conn.Close()
+		return err
+	}
+	return nil
+}
+
+// Clone returns a copy of the player with its own hand. The connection is shared.
+func (p *Player) Clone() *Player {
+	clone := *p
+	clone.Hand = cloneCards(p.Hand)
+	return &clone
 }
 
 // In game/game.go
@@ -68,18 +367,22 @@ type SavedPlayerData struct {
 	Team      string
 	Index     int
 	IsLeaving bool
-	RoomID    string // Add this field
+	RoomID    string    // Add this field
+	SavedAt   time.Time // When the seat was saved, used to expire it
 }
 
 // WSMessage represents a WebSocket message
 type WSMessage struct {
-	Action string      `json:"action"` // e.g., "play_card", "choose_trump"
-	Data   interface{} `json:"data"`   // Additional data (e.g., card played, trump suit)
+	Action string          `json:"action"`       // e.g., "play_card", "choose_trump"
+	ID     string          `json:"id,omitempty"` // Client correlation id, echoed in the ack
+	Data   json.RawMessage `json:"data"`         // Additional data (e.g., card played, trump suit), decoded per action
 }
 
 type WSResponse struct {
 	Type    string      `json:"type"`
 	Payload interface{} `json:"payload"`
+	Seq     uint64      `json:"seq,omitempty"`      // Position in the room's EventLog, set when sent in a room
+	PrevSeq uint64      `json:"prev_seq,omitempty"` // Seq of the previous message to the same client; another last seen Seq means a gap
 }
 
 var Manager = GameManager{
@@ -90,18 +393,22 @@ var Manager = GameManager{
 // Initialize RoundScores when creating a new Game
 func NewGame() *Game {
 	return &Game{
-		Deck:               []Card{},             // Initialize Deck
-		TrumpSuit:          "",                   // Initialize TrumpSuit
-		Players:            []*Player{},          // Initialize Players
-		CurrentTrick:       []Card{},             // Initialize CurrentTrick
-		TrickPlayOrder:     []*Player{},          // Initialize TrickPlayOrder
-		Scores:             make(map[string]int), // Initialize Scores
-		RoundScores:        make(map[string]int), // Initialize RoundScores
-		CurrentPlayerIndex: 0,                    // Initialize CurrentPlayerIndex
-		DealerIndex:        0,                    // Initialize DealerIndex
-		TrumpPlayer:        nil,                  // Initialize TrumpPlayer
-		CurrentRound:       1,                    // Initialize CurrentRound (start with Round 1)
-		IsGameOver:         false,                // Initialize IsGameOver
+		Deck:             []Card{},             // Initialize Deck
+		TrumpSuit:        "",                   // Initialize TrumpSuit
+		Players:          []*Player{},          // Initialize Players
+		CurrentTrick:     []Card{},             // Initialize CurrentTrick
+		TrickPlayOrder:   []*Player{},          // Initialize TrickPlayOrder
+		Scores:           make(map[string]int), // Initialize Scores
+		RoundScores:      make(map[string]int), // Initialize RoundScores
+		CurrentPlayerID:  "",                   // Initialize CurrentPlayerID
+		DealerIndex:      0,                    // Initialize DealerIndex
+		TrumpPlayer:      nil,                  // Initialize TrumpPlayer
+		CurrentRound:     1,                    // Initialize CurrentRound (start with Round 1)
+		TricksWon:        make(map[string]int), // Initialize TricksWon
+		TotalTricksWon:   make(map[string]int), // Initialize TotalTricksWon
+		Kots:             make(map[string]int), // Initialize Kots
+		TricksToWinRound: 7,                    // Standard deck: 7 of 13 tricks
+		Phase:            PhaseLobby,           // Initialize Phase
 	}
 }
 
@@ -116,33 +423,393 @@ func (gm *GameManager) CreateRoom() *Room {
 	gm.Mu.Lock()
 	defer gm.Mu.Unlock()
 
-	roomID := GenerateRoomID()
-	room := &Room{
-		ID:      roomID,
-		Players: []*Player{},
-		Game:    NewGame(),
-	}
-	gm.Rooms[roomID] = room
+	room := NewRoom(DefaultRoomSettings())
+	gm.Rooms[room.ID] = room
 	return room
 }
 
+// FindRoomByUserID returns the room and player seated for the given account
+func (gm *GameManager) FindRoomByUserID(userID string) (*Room, *Player) {
+	if userID == "" {
+		return nil, nil
+	}
+
+	gm.Mu.RLock()
+	defer gm.Mu.RUnlock()
+	return gm.FindRoomByUserIDLocked(userID)
+}
+
+// FindRoomByUserIDLocked is FindRoomByUserID for callers already holding Mu
+func (gm *GameManager) FindRoomByUserIDLocked(userID string) (*Room, *Player) {
+	if userID == "" {
+		return nil, nil
+	}
+
+	for _, room := range gm.Rooms {
+		for _, p := range room.Players {
+			if p.UserID == userID {
+				return room, p
+			}
+		}
+	}
+	return nil, nil
+}
+
+// NewRoom creates an empty room with a fresh game and the given settings
+func NewRoom(settings RoomSettings) *Room {
+	now := time.Now()
+	return &Room{
+		ID:             GenerateRoomID(),
+		Players:        []*Player{},
+		Game:           newGameFor(settings),
+		Settings:       settings,
+		CreatedAt:      now,
+		BelowFullSince: now,
+		Events:         NewEventLog(),
+	}
+}
+
+// Track numbers what the player is sent from now on with the room's EventLog. Call
+// it whenever a player is seated in the room.
+func (r *Room) Track(p *Player) {
+	p.Events = r.Events
+	if p.Conn != nil {
+		p.Conn.SetEvents(r.Events, p.ID)
+	}
+}
+
+// UpdateBelowFull keeps BelowFullSince in step with the seats. Call it whenever
+// a player joins or leaves; a room that was already short keeps its time.
+func (r *Room) UpdateBelowFull(now time.Time) {
+	if len(r.Players) >= r.Settings.Seats() {
+		r.BelowFullSince = time.Time{}
+	} else if r.BelowFullSince.IsZero() {
+		r.BelowFullSince = now
+	}
+}
+
+// newGameFor creates a fresh game following the room settings
+func newGameFor(settings RoomSettings) *Game {
+	g := NewGame()
+	g.TricksToWinRound = settings.TricksToWinRound()
+	g.CardOrder = settings.CardOrder
+	return g
+}
+
+// ResetGame replaces the finished game with a fresh one for the same seated players
+func (r *Room) ResetGame() {
+	g := newGameFor(r.Settings)
+	for _, p := range r.Players {
+		p.Hand = []Card{}
+		p.Ready = false
+		g.Players = append(g.Players, p)
+	}
+	r.Game = g
+	r.KickVotes = nil
+	r.RematchVotes = nil
+}
+
+// DefaultRoomSettings returns the room settings configured through the environment
+func DefaultRoomSettings() RoomSettings {
+	policy := config.GetEnv("DISCONNECT_POLICY", DisconnectWaitReplacement)
+	if policy != DisconnectForfeit {
+		policy = DisconnectWaitReplacement
+	}
+
+	deckVariant := config.GetEnv("DECK_VARIANT", DeckStandard)
+	if deckVariant != DeckStripped {
+		deckVariant = DeckStandard
+	}
+
+	tableSize := config.GetEnvInt("TABLE_SIZE", 4)
+	if tableSize != 6 {
+		tableSize = 4
+	}
+	// 32 cards can't be dealt evenly to 6 players
+	if tableSize == 6 && deckVariant == DeckStripped {
+		log.Printf("The stripped deck can't be dealt to 6 players, using the standard deck")
+		deckVariant = DeckStandard
+	}
+	sizing := RoomSettings{DeckVariant: deckVariant, TableSize: tableSize}
+
+	gameMode := config.GetEnv("GAME_MODE", ModeStandard)
+	if gameMode != ModeDark {
+		gameMode = ModeStandard
+	}
+
+	trickOnLeave := config.GetEnv("TRICK_ON_LEAVE", TrickKeep)
+	if trickOnLeave != TrickDiscard {
+		trickOnLeave = TrickKeep
+	}
+
+	reconnectTimeout := config.GetEnvDuration("RECONNECT_TIMEOUT", DefaultReconnectTimeout)
+	if reconnectTimeout < MinReconnectTimeout || reconnectTimeout > MaxReconnectTimeout {
+		log.Printf("RECONNECT_TIMEOUT %v is outside %v-%v, using %v", reconnectTimeout, MinReconnectTimeout, MaxReconnectTimeout, DefaultReconnectTimeout)
+		reconnectTimeout = DefaultReconnectTimeout
+	}
+
+	trumpSelection := config.GetEnv("TRUMP_SELECTION", TrumpSelectionAce)
+	if trumpSelection != TrumpSelectionHighest && trumpSelection != TrumpSelectionFixed {
+		trumpSelection = TrumpSelectionAce
+	}
+
+	cardOrder := config.GetEnv("CARD_ORDER", CardOrderAceHigh)
+	if _, ok := CardOrders[cardOrder]; !ok {
+		log.Printf("Unknown CARD_ORDER %q, using %s", cardOrder, CardOrderAceHigh)
+		cardOrder = CardOrderAceHigh
+	}
+
+	botLevel := config.GetEnv("BOT_LEVEL", BotNormal)
+	if botLevel != BotEasy && botLevel != BotHard {
+		botLevel = BotNormal
+	}
+
+	defaults := DefaultScoringRules()
+	scoring := ScoringRules{
+		Normal:   config.GetEnvInt("SCORE_NORMAL", defaults.Normal),
+		Kot:      config.GetEnvInt("SCORE_KOT", defaults.Kot),
+		TrumpKot: config.GetEnvInt("SCORE_TRUMP_KOT", defaults.TrumpKot),
+	}
+	if err := scoring.Validate(); err != nil {
+		log.Printf("Invalid scoring rules, using defaults: %v", err)
+		scoring = defaults
+	}
+
+	dealPattern := DefaultDealPattern(sizing.CardsPerPlayer())
+	if value := config.GetEnv("DEAL_PATTERN", ""); value != "" {
+		pattern, err := parseDealPattern(value)
+		if err == nil {
+			err = ValidateDealPattern(pattern, sizing.CardsPerPlayer())
+		}
+		if err != nil {
+			log.Printf("Invalid DEAL_PATTERN %q, using %v: %v", value, dealPattern, err)
+		} else {
+			dealPattern = pattern
+		}
+	}
+
+	return RoomSettings{
+		DisconnectPolicy: policy,
+		DeckVariant:      deckVariant,
+		GameMode:         gameMode,
+		Scoring:          scoring,
+		DealPattern:      dealPattern,
+		TrickOnLeave:     trickOnLeave,
+		TableSize:        tableSize,
+		TrumpSelection:   trumpSelection,
+		ReconnectTimeout: reconnectTimeout,
+		CardOrder:        cardOrder,
+		BotLevel:         botLevel,
+	}
+}
+
+// parseDealPattern reads a comma-separated pattern such as "4,4,5"
+func parseDealPattern(value string) ([]int, error) {
+	var pattern []int
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		pattern = append(pattern, n)
+	}
+	return pattern, nil
+}
+
 func GenerateRoomID() string {
 	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	b := make([]byte, 6)
 	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+		b[i] = letters[Random.Intn(len(letters))]
 	}
 	return string(b)
 }
 
+// SuitOrder is the order suits are grouped in when sorting a hand
+var SuitOrder = []string{"spades", "hearts", "clubs", "diamonds"}
+
+// SortHand returns a copy of the hand grouped by suit and ordered by descending
+// value within each suit. Cards of the trump suit, if any, come first.
+func SortHand(hand []Card, trumpSuit string) []Card {
+	rank := make(map[string]int)
+	for i, suit := range SuitOrder {
+		rank[suit] = i + 1
+	}
+	if trumpSuit != "" {
+		rank[trumpSuit] = 0
+	}
+
+	sorted := append([]Card{}, hand...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Suit != sorted[j].Suit {
+			return rank[sorted[i].Suit] < rank[sorted[j].Suit]
+		}
+		return sorted[i].Value > sorted[j].Value
+	})
+	return sorted
+}
+
+// TeamForSeat maps a seat to its team. Partners sit across from each other,
+// so seats 0 and 2 play together against seats 1 and 3.
+func TeamForSeat(seatIndex int) string {
+	if seatIndex%2 == 0 {
+		return "team2"
+	}
+	return "team1"
+}
+
+// ValidateTeams checks that the seated players are split evenly between the two
+// teams with one player per seat. A player whose team doesn't match their seat
+// is moved to the right team first.
+func (r *Room) ValidateTeams() error {
+	seats := make(map[int]bool)
+	counts := make(map[string]int)
+
+	for _, p := range r.Players {
+		if seats[p.Index] {
+			return fmt.Errorf("seat %d is taken by more than one player", p.Index)
+		}
+		seats[p.Index] = true
+
+		if team := TeamForSeat(p.Index); p.Team != team {
+			log.Printf("Player %s in seat %d was on %s, moving to %s", p.ID, p.Index, p.Team, team)
+			p.Team = team
+		}
+		counts[p.Team]++
+	}
+
+	if counts["team1"] != counts["team2"] {
+		return fmt.Errorf("teams are imbalanced: %d vs %d", counts["team1"], counts["team2"])
+	}
+	return nil
+}
+
 func (r *Room) SortPlayers() {
 	sort.Slice(r.Players, func(i, j int) bool {
 		return r.Players[i].Index < r.Players[j].Index
 	})
 }
 
+// CurrentPlayer returns the player whose turn it is, or nil if nobody's turn is set
+func (g *Game) CurrentPlayer() *Player {
+	for _, p := range g.Players {
+		if p.ID == g.CurrentPlayerID {
+			return p
+		}
+	}
+	return nil
+}
+
+// CurrentPlayerIndex returns the position of the current player in Players, or -1
+func (g *Game) CurrentPlayerIndex() int {
+	for i, p := range g.Players {
+		if p.ID == g.CurrentPlayerID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Leads follow the Hokm rules: the Trump Player (Hakem) leads the first trick of a
+// Round and the winner of each trick leads the next one. SetRoundLeader and
+// SetTrickLeader are the only places that hand out the lead.
+
+// SetRoundLeader gives the lead of a Round's first trick to the Trump Player.
+// It reports whether the Trump Player is seated.
+func (g *Game) SetRoundLeader() bool {
+	if g.TrumpPlayer == nil {
+		return false
+	}
+	return g.SetTrickLeader(g.TrumpPlayer.ID)
+}
+
+// Dealer returns the dealer of the current Round, or nil before anyone is seated
+func (g *Game) Dealer() *Player {
+	if len(g.Players) == 0 {
+		return nil
+	}
+	return g.Players[g.DealerIndex%len(g.Players)]
+}
+
+// AdvanceDealer passes the deal one seat on, as it does every Round. The Trump
+// Player is decided separately, by who won the Round.
+func (g *Game) AdvanceDealer() {
+	if len(g.Players) == 0 {
+		return
+	}
+	g.DealerIndex = (g.DealerIndex + 1) % len(g.Players)
+}
+
+// DrawOrder returns the players starting with the one after the dealer, the
+// order cards go round the table in
+func (g *Game) DrawOrder() []*Player {
+	n := len(g.Players)
+	order := make([]*Player, 0, n)
+	for i := 1; i <= n; i++ {
+		order = append(order, g.Players[(g.DealerIndex+i)%n])
+	}
+	return order
+}
+
+// SetTrickLeader gives the lead of the next trick to the winner of the last one, by ID
+// rather than by position so it holds however Players is ordered. It reports whether
+// the player was found.
+func (g *Game) SetTrickLeader(winnerID string) bool {
+	for _, p := range g.Players {
+		if p.ID == winnerID {
+			g.CurrentPlayerID = winnerID
+			return true
+		}
+	}
+	return false
+}
+
+// expectedLeader returns who must lead the current trick: the Trump Player on the
+// first trick of the Round, otherwise the winner of the previous trick
+func (g *Game) expectedLeader() string {
+	if n := len(g.RoundTricks); n > 0 {
+		return g.RoundTricks[n-1].WinnerID
+	}
+	if g.TrumpPlayer != nil {
+		return g.TrumpPlayer.ID
+	}
+	return ""
+}
+
+// NextTurn passes the turn to the next player in seat order
 func (g *Game) NextTurn() {
-	g.CurrentPlayerIndex = (g.CurrentPlayerIndex + 1) % len(g.Players)
+	i := g.CurrentPlayerIndex()
+	if i == -1 {
+		return
+	}
+	g.CurrentPlayerID = g.Players[(i+1)%len(g.Players)].ID
+}
+
+// PlayMove is PlayCard for a client-supplied moveID, so a client that resends a
+// play_card after a missed reply can't play twice. A moveID the player already
+// played this Round returns ErrDuplicateMove and changes nothing; reusing it for
+// another card is an error. Without a moveID it's just PlayCard.
+func (g *Game) PlayMove(playerID, moveID string, card Card) error {
+	if moveID == "" {
+		return g.PlayCard(playerID, card)
+	}
+
+	key := playerID + "/" + moveID
+	if played, ok := g.AppliedMoves[key]; ok {
+		if played.Suit != card.Suit || played.Rank != card.Rank {
+			return fmt.Errorf("move %s was already used for another card", moveID)
+		}
+		return ErrDuplicateMove
+	}
+
+	if err := g.PlayCard(playerID, card); err != nil {
+		return err
+	}
+	if g.AppliedMoves == nil {
+		g.AppliedMoves = make(map[string]Card)
+	}
+	g.AppliedMoves[key] = card
+	return nil
 }
 
 // Play a card in the current trick
@@ -153,10 +820,12 @@ func (g *Game) PlayCard(playerID string, card Card) error {
 	}
 
 	// Check if it's the player's turn
-	currentPlayer := g.Players[g.CurrentPlayerIndex]
-
-	if currentPlayer.ID != playerID {
-		return fmt.Errorf("it's not your turn")
+	player := g.CurrentPlayer()
+	if player == nil || player.ID != playerID {
+		return ErrNotYourTurn
+	}
+	if len(g.CurrentTrick) == 0 && playerID != g.expectedLeader() {
+		return fmt.Errorf("the trick must be led by %s", g.expectedLeader())
 	}
 
 	// Validate the card
@@ -164,11 +833,11 @@ func (g *Game) PlayCard(playerID string, card Card) error {
 		return fmt.Errorf("invalid card play")
 	}
 
-	player := g.Players[g.CurrentPlayerIndex]
 	g.TrickPlayOrder = append(g.TrickPlayOrder, player)
 
 	// Add the card to the current trick
 	g.CurrentTrick = append(g.CurrentTrick, card)
+	g.LastPlayAt = time.Now()
 
 	// Move to the next player
 	g.NextTurn()
@@ -176,38 +845,204 @@ func (g *Game) PlayCard(playerID string, card Card) error {
 	return nil
 }
 
-// Determine the winner of the current trick
-func (g *Game) DetermineTrickWinner(players []*Player) string {
+// RecordMove appends a played card to the replay log
+func (g *Game) RecordMove(playerID string, card Card) {
+	g.Moves = append(g.Moves, MoveRecord{
+		Action:    "play_card",
+		PlayerID:  playerID,
+		Team:      g.teamOf(playerID),
+		Card:      &card,
+		Timestamp: time.Now(),
+	})
+}
+
+// teamOf returns the team of the seated player, or "" if they aren't seated
+func (g *Game) teamOf(playerID string) string {
+	for _, p := range g.Players {
+		if p.ID == playerID {
+			return p.Team
+		}
+	}
+	return ""
+}
+
+// Renege is a card played off the led suit while its player still held that suit
+type Renege struct {
+	PlayerID string
+	Card     Card
+	LeadSuit string
+}
+
+// AuditTrick re-checks a completed trick after the fact, as a safety net behind
+// ValidateCardPlay. A player's hand when they played was the hand they hold now plus
+// the card they played, so anyone who went off-suit must hold none of the led suit now.
+func (g *Game) AuditTrick() []Renege {
 	if len(g.CurrentTrick) == 0 || len(g.TrickPlayOrder) != len(g.CurrentTrick) {
-		return ""
+		return nil
+	}
+
+	leadSuit := g.CurrentTrick[0].Suit
+	var reneges []Renege
+	for i, card := range g.CurrentTrick[1:] {
+		if card.Suit == leadSuit {
+			continue
+		}
+		playerID := g.TrickPlayOrder[i+1].ID
+		for _, p := range g.Players {
+			if p.ID == playerID && holdsSuit(p.Hand, leadSuit) {
+				reneges = append(reneges, Renege{PlayerID: playerID, Card: card, LeadSuit: leadSuit})
+			}
+		}
 	}
+	return reneges
+}
+
+// holdsSuit reports whether the hand has any card of the suit
+func holdsSuit(hand []Card, suit string) bool {
+	for _, c := range hand {
+		if c.Suit == suit {
+			return true
+		}
+	}
+	return false
+}
 
+// RecordRenege flags a renege found by AuditTrick in the replay log for investigation
+func (g *Game) RecordRenege(r Renege) {
+	card := r.Card
+	g.Moves = append(g.Moves, MoveRecord{
+		Action:    "renege_detected",
+		PlayerID:  r.PlayerID,
+		Card:      &card,
+		Timestamp: time.Now(),
+	})
+}
+
+// RecordForfeit appends the forfeit of the losing team to the replay log
+func (g *Game) RecordForfeit(losingTeam string) {
+	g.Moves = append(g.Moves, MoveRecord{
+		Action:    "forfeit",
+		Team:      losingTeam,
+		Timestamp: time.Now(),
+	})
+}
+
+// RecordTrumpChoice appends the chosen trump suit to the replay log
+func (g *Game) RecordTrumpChoice(playerID string, suit string) {
+	g.Moves = append(g.Moves, MoveRecord{
+		Action:    "choose_trump",
+		PlayerID:  playerID,
+		Team:      g.teamOf(playerID),
+		TrumpSuit: suit,
+		Timestamp: time.Now(),
+	})
+}
+
+// UndoLastPlay takes the last card of the current trick back into the hand of
+// the player who played it, as long as nobody has played after them and the
+// undo window hasn't passed
+func (g *Game) UndoLastPlay(playerID string) (Card, error) {
+	last := len(g.CurrentTrick) - 1
+	if last < 0 || len(g.TrickPlayOrder) != len(g.CurrentTrick) {
+		return Card{}, fmt.Errorf("no card to undo")
+	}
+
+	player := g.TrickPlayOrder[last]
+	if player.ID != playerID {
+		return Card{}, fmt.Errorf("only the last card played can be undone")
+	}
+	if time.Since(g.LastPlayAt) > UndoWindow {
+		return Card{}, fmt.Errorf("undo window has passed")
+	}
+
+	card := g.CurrentTrick[last]
+	g.CurrentTrick = g.CurrentTrick[:last]
+	g.TrickPlayOrder = g.TrickPlayOrder[:last]
+	player.Hand = append(player.Hand, card)
+
+	// The undone card never counts as a move
+	if n := len(g.Moves); n > 0 && g.Moves[n-1].Action == "play_card" && g.Moves[n-1].PlayerID == playerID {
+		g.Moves = g.Moves[:n-1]
+	}
+
+	// Give the turn back to the player
+	g.CurrentPlayerID = playerID
+	return card, nil
+}
+
+// TrickPlay is a card of the current trick with the player who played it
+type TrickPlay struct {
+	PlayerID string `json:"player_id"`
+	Card     Card   `json:"card"`
+}
+
+// TrickPlays pairs each card of the current trick with its player, in play order
+func (g *Game) TrickPlays() []TrickPlay {
+	plays := []TrickPlay{}
+	for i, card := range g.CurrentTrick {
+		if i >= len(g.TrickPlayOrder) {
+			break
+		}
+		plays = append(plays, TrickPlay{PlayerID: g.TrickPlayOrder[i].ID, Card: card})
+	}
+	return plays
+}
+
+// Determine the winner of the current trick
+func (g *Game) DetermineTrickWinner(players []*Player) (string, error) {
+	if len(g.CurrentTrick) == 0 {
+		return "", fmt.Errorf("no cards in the current trick")
+	}
+	if len(g.TrickPlayOrder) != len(g.CurrentTrick) {
+		return "", fmt.Errorf("trick has %d cards but %d recorded plays", len(g.CurrentTrick), len(g.TrickPlayOrder))
+	}
+
+	winnerIndex := g.winningIndex()
+
+	// Use TrickPlayOrder instead of players slice
+	return g.TrickPlayOrder[winnerIndex].ID, nil
+}
+
+// winningIndex returns the position of the card currently winning the trick
+func (g *Game) winningIndex() int {
 	leadingSuit := g.CurrentTrick[0].Suit
 	winningCard := g.CurrentTrick[0]
 	winnerIndex := 0
 
 	for i, card := range g.CurrentTrick {
-		if card.Suit == g.TrumpSuit {
-			if winningCard.Suit != g.TrumpSuit {
-				winningCard = card
-				winnerIndex = i
-			} else if card.Value > winningCard.Value {
-				winningCard = card
-				winnerIndex = i
-			}
-		} else if card.Suit == leadingSuit && winningCard.Suit != g.TrumpSuit {
-			if card.Value > winningCard.Value {
-				winningCard = card
-				winnerIndex = i
-			}
+		if g.beats(card, winningCard, leadingSuit) {
+			winningCard = card
+			winnerIndex = i
 		}
 	}
+	return winnerIndex
+}
 
-	// Use TrickPlayOrder instead of players slice
-	if winnerIndex < len(g.TrickPlayOrder) {
-		return g.TrickPlayOrder[winnerIndex].ID
+// beats reports whether card takes the trick over the current winning card
+func (g *Game) beats(card Card, winningCard Card, leadingSuit string) bool {
+	if card.Suit == g.TrumpSuit {
+		return winningCard.Suit != g.TrumpSuit || card.Value > winningCard.Value
 	}
-	return ""
+	return card.Suit == leadingSuit && winningCard.Suit != g.TrumpSuit && card.Value > winningCard.Value
+}
+
+// LegalCards returns the cards of the hand that may be played into the current trick
+func (g *Game) LegalCards(hand []Card) []Card {
+	if len(g.CurrentTrick) == 0 {
+		return hand
+	}
+
+	leadingSuit := g.CurrentTrick[0].Suit
+	var following []Card
+	for _, c := range hand {
+		if c.Suit == leadingSuit {
+			following = append(following, c)
+		}
+	}
+	if len(following) == 0 {
+		return hand
+	}
+	return following
 }
 
 // Add this to reset play order when starting new trick
@@ -216,6 +1051,24 @@ func (g *Game) ResetTrick() {
 	g.TrickPlayOrder = []*Player{}
 }
 
+// ReturnTrick takes an unfinished trick back: every card goes back to the hand that
+// played it, the plays are dropped from the replay log and whoever led leads again
+func (g *Game) ReturnTrick() {
+	if len(g.CurrentTrick) == 0 || len(g.TrickPlayOrder) != len(g.CurrentTrick) {
+		return
+	}
+
+	for i, p := range g.TrickPlayOrder {
+		p.Hand = append(p.Hand, g.CurrentTrick[i])
+	}
+	for n := len(g.CurrentTrick); n > 0 && len(g.Moves) > 0 && g.Moves[len(g.Moves)-1].Action == "play_card"; n-- {
+		g.Moves = g.Moves[:len(g.Moves)-1]
+	}
+
+	g.CurrentPlayerID = g.TrickPlayOrder[0].ID
+	g.ResetTrick()
+}
+
 // Update scores based on the number of tricks won
 func (g *Game) UpdateScores(team string, tricksWon int) {
 	if g.Scores == nil {
@@ -224,6 +1077,243 @@ func (g *Game) UpdateScores(team string, tricksWon int) {
 	g.Scores[team] += tricksWon
 }
 
+// RecordTrickWinner credits a trick to the player who won it
+func (g *Game) RecordTrickWinner(playerID string) {
+	if g.TricksWon == nil {
+		g.TricksWon = make(map[string]int)
+	}
+	if g.TotalTricksWon == nil {
+		g.TotalTricksWon = make(map[string]int)
+	}
+	g.TricksWon[playerID]++
+	g.TotalTricksWon[playerID]++
+}
+
+// IsRoundOver reports whether the Round is decided: a team took TricksToWinRound
+// tricks, or every card has been played. With 8 cards each (the stripped deck or a
+// 3v3 table) the tricks can split 4-4 and nobody reaches the target; a tie then
+// goes against the Trump team, who failed to win their Round. Without a Trump
+// Player a tied Round is over with no winner.
+func (g *Game) IsRoundOver() (bool, string) {
+	if over, team := leadingTeam(g.Scores, g.TricksToWinRound); over {
+		return true, team
+	}
+	if g.Scores["team1"]+g.Scores["team2"] == 0 || !g.AllHandsEmpty() {
+		return false, ""
+	}
+
+	switch {
+	case g.Scores["team1"] > g.Scores["team2"]:
+		return true, "team1"
+	case g.Scores["team2"] > g.Scores["team1"]:
+		return true, "team2"
+	case g.TrumpPlayer == nil:
+		return true, ""
+	case g.TrumpPlayer.Team == "team1":
+		return true, "team2"
+	default:
+		return true, "team1"
+	}
+}
+
+// AllHandsEmpty reports whether every seated player has played their last card
+func (g *Game) AllHandsEmpty() bool {
+	for _, p := range g.Players {
+		if len(p.Hand) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsMatchOver reports whether a team has won enough Rounds to take the match
+func (g *Game) IsMatchOver() (bool, string) {
+	return leadingTeam(g.RoundScores, TargetScore)
+}
+
+// leadingTeam returns the first team (in team order) whose score reached the target
+func leadingTeam(scores map[string]int, target int) (bool, string) {
+	for _, team := range []string{"team1", "team2"} {
+		if scores[team] >= target {
+			return true, team
+		}
+	}
+	return false, ""
+}
+
+// LastTrick returns the trick that was just completed while it may still be
+// reviewed: within PeekWindow and before the next card is led
+func (g *Game) LastTrick() (TrickRecord, bool) {
+	if len(g.RoundTricks) == 0 || len(g.CurrentTrick) > 0 || time.Since(g.LastTrickAt) > PeekWindow {
+		return TrickRecord{}, false
+	}
+	return g.RoundTricks[len(g.RoundTricks)-1], true
+}
+
+// Clone returns a deep copy of the game that shares no slices or maps with it,
+// for snapshots that must not change with the live state
+func (g *Game) Clone() *Game {
+	clone := *g
+	clone.Deck = cloneCards(g.Deck)
+	clone.CurrentTrick = cloneCards(g.CurrentTrick)
+	clone.Scores = cloneCounts(g.Scores)
+	clone.RoundScores = cloneCounts(g.RoundScores)
+	clone.TricksWon = cloneCounts(g.TricksWon)
+	clone.TotalTricksWon = cloneCounts(g.TotalTricksWon)
+	clone.Kots = cloneCounts(g.Kots)
+	if g.AppliedMoves != nil {
+		clone.AppliedMoves = make(map[string]Card, len(g.AppliedMoves))
+		for k, c := range g.AppliedMoves {
+			clone.AppliedMoves[k] = c
+		}
+	}
+
+	// Keep player pointers consistent inside the clone
+	players := make(map[*Player]*Player, len(g.Players))
+	cloneOf := func(p *Player) *Player {
+		if p == nil {
+			return nil
+		}
+		if c, ok := players[p]; ok {
+			return c
+		}
+		c := p.Clone()
+		players[p] = c
+		return c
+	}
+
+	clone.Players = make([]*Player, len(g.Players))
+	for i, p := range g.Players {
+		clone.Players[i] = cloneOf(p)
+	}
+	clone.TrickPlayOrder = make([]*Player, len(g.TrickPlayOrder))
+	for i, p := range g.TrickPlayOrder {
+		clone.TrickPlayOrder[i] = cloneOf(p)
+	}
+	clone.TrumpPlayer = cloneOf(g.TrumpPlayer)
+
+	clone.Moves = make([]MoveRecord, len(g.Moves))
+	for i, m := range g.Moves {
+		if m.Card != nil {
+			card := *m.Card
+			m.Card = &card
+		}
+		clone.Moves[i] = m
+	}
+
+	clone.RoundTricks = make([]TrickRecord, len(g.RoundTricks))
+	for i, t := range g.RoundTricks {
+		t.Cards = cloneCards(t.Cards)
+		t.PlayerIDs = append([]string(nil), t.PlayerIDs...)
+		clone.RoundTricks[i] = t
+	}
+	return &clone
+}
+
+func cloneCards(cards []Card) []Card {
+	if cards == nil {
+		return nil
+	}
+	return append([]Card{}, cards...)
+}
+
+func cloneCounts(counts map[string]int) map[string]int {
+	if counts == nil {
+		return nil
+	}
+	clone := make(map[string]int, len(counts))
+	for k, v := range counts {
+		clone[k] = v
+	}
+	return clone
+}
+
+// RecordTrick adds the completed current trick to the Round's trick history
+func (g *Game) RecordTrick(winnerID string) {
+	record := TrickRecord{
+		Cards:    cloneCards(g.CurrentTrick),
+		WinnerID: winnerID,
+	}
+	for _, p := range g.TrickPlayOrder {
+		record.PlayerIDs = append(record.PlayerIDs, p.ID)
+	}
+
+	g.RoundTricks = append(g.RoundTricks, record)
+	g.LastTrickAt = time.Now()
+	if len(g.RoundTricks) > MaxRoundTricks {
+		g.RoundTricks = g.RoundTricks[len(g.RoundTricks)-MaxRoundTricks:]
+	}
+}
+
+// ValidateDeal checks a deal for consistency: every seat holds exactly expected[ID]
+// cards, no card appears twice and the hands plus the undealt deck make up all
+// deckSize cards.
+func (g *Game) ValidateDeal(expected map[string]int, deckSize int) error {
+	seen := make(map[Card]bool, deckSize)
+	add := func(c Card, where string) error {
+		if seen[c] {
+			return fmt.Errorf("card %s of %s dealt twice (again in %s)", c.Rank, c.Suit, where)
+		}
+		seen[c] = true
+		return nil
+	}
+
+	for _, p := range g.Players {
+		if len(p.Hand) != expected[p.ID] {
+			return fmt.Errorf("player %s holds %d cards, expected %d", p.ID, len(p.Hand), expected[p.ID])
+		}
+		for _, c := range p.Hand {
+			if err := add(c, "the hand of "+p.ID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range g.Deck {
+		if err := add(c, "the deck"); err != nil {
+			return err
+		}
+	}
+
+	if len(seen) != deckSize {
+		return fmt.Errorf("deal accounts for %d cards, expected %d", len(seen), deckSize)
+	}
+	return nil
+}
+
+// ReconcileHand drops the cards of a restored hand that are no longer the player's:
+// cards already played this Round and cards held by another seat. It returns the
+// hand that's left and the stale cards it removed.
+func (g *Game) ReconcileHand(playerID string, hand []Card) ([]Card, []Card) {
+	elsewhere := make(map[Card]bool)
+	for _, trick := range g.RoundTricks {
+		for _, c := range trick.Cards {
+			elsewhere[c] = true
+		}
+	}
+	for _, c := range g.CurrentTrick {
+		elsewhere[c] = true
+	}
+	for _, p := range g.Players {
+		if p.ID == playerID {
+			continue
+		}
+		for _, c := range p.Hand {
+			elsewhere[c] = true
+		}
+	}
+
+	kept := make([]Card, 0, len(hand))
+	var stale []Card
+	for _, c := range hand {
+		if elsewhere[c] {
+			stale = append(stale, c)
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, stale
+}
+
 // Check if a team has won the game
 func (g *Game) CheckForWinner(targetScore int) string {
 	for team, score := range g.Scores {
@@ -234,11 +1324,10 @@ func (g *Game) CheckForWinner(targetScore int) string {
 	return ""
 }
 
-func (g *Game) ChooseTrumpSuit(dealerID string, suit string) error {
-	// Check if the dealer is choosing the suit
-	dealer := g.Players[g.DealerIndex]
-	if dealer.ID != dealerID {
-		return fmt.Errorf("only the dealer can choose the trump suit")
+func (g *Game) ChooseTrumpSuit(playerID string, suit string) error {
+	// Trump is the Trump Player's call, whoever deals
+	if g.TrumpPlayer == nil || g.TrumpPlayer.ID != playerID {
+		return fmt.Errorf("only the Trump Player can choose the trump suit")
 	}
 
 	// Set the trump suit
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	ErrPlayerNotFound = errors.New("player is not seated in any room")
+	ErrRoomNotFound   = errors.New("room not found")
+	ErrSameRoom       = errors.New("player is already in that room")
+	ErrRoomStarted    = errors.New("room has already started")
+	ErrRoomFull       = errors.New("room is full")
+)
+
+// FreeSeat returns the lowest seat index not held by an active or saved player
+func (r *Room) FreeSeat() int {
+	taken := make(map[int]bool)
+	for _, p := range r.Players {
+		taken[p.Index] = true
+	}
+	for _, data := range r.SavedPlayers {
+		taken[data.Index] = true
+	}
+
+	seat := 0
+	for taken[seat] {
+		seat++
+	}
+	return seat
+}
+
+// Seat puts the player into the game's player list, taking over the entry
+// of whoever held the same seat before, along with their place as Trump Player
+// and in the current trick
+func (g *Game) Seat(player *Player) {
+	for i, p := range g.Players {
+		if p.Index == player.Index {
+			g.Players[i] = player
+			if g.TrumpPlayer == p {
+				g.TrumpPlayer = player
+			}
+			for j, played := range g.TrickPlayOrder {
+				if played == p {
+					g.TrickPlayOrder[j] = player
+				}
+			}
+			return
+		}
+	}
+	g.Players = append(g.Players, player)
+	sort.Slice(g.Players, func(i, j int) bool {
+		return g.Players[i].Index < g.Players[j].Index
+	})
+}
+
+// RemovePlayer takes the player out of the room and its game, along with any votes they cast
+func (r *Room) RemovePlayer(player *Player) {
+	for i, p := range r.Players {
+		if p == player {
+			r.Players = append(r.Players[:i], r.Players[i+1:]...)
+			break
+		}
+	}
+	for i, p := range r.Game.Players {
+		if p == player {
+			r.Game.Players = append(r.Game.Players[:i], r.Game.Players[i+1:]...)
+			break
+		}
+	}
+	delete(r.KickVotes, player.ID)
+	for _, voters := range r.KickVotes {
+		delete(voters, player.ID)
+	}
+	delete(r.RematchVotes, player.ID)
+	r.DropSwapRequests(player.ID)
+	r.UpdateBelowFull(time.Now())
+}
+
+// SwapSeats exchanges the seats of two players, and with them their teams
+func (r *Room) SwapSeats(a, b *Player) {
+	a.Index, b.Index = b.Index, a.Index
+	a.Team = TeamForSeat(a.Index)
+	b.Team = TeamForSeat(b.Index)
+
+	r.SortPlayers()
+	sort.Slice(r.Game.Players, func(i, j int) bool {
+		return r.Game.Players[i].Index < r.Game.Players[j].Index
+	})
+}
+
+// DropSwapRequests forgets every seat swap the player asked for or was asked for
+func (r *Room) DropSwapRequests(playerID string) {
+	delete(r.SwapRequests, playerID)
+	for from, to := range r.SwapRequests {
+		if to == playerID {
+			delete(r.SwapRequests, from)
+		}
+	}
+}
+
+// MovePlayer moves a lobby player to the first free seat of another lobby room,
+// taking the team of that seat. Both rooms are checked and changed under a single
+// lock so nobody can join in between. It returns the room the player left so the
+// caller can tell both rooms about the new rosters.
+func (gm *GameManager) MovePlayer(playerID, targetRoomID string) (*Room, error) {
+	gm.Mu.Lock()
+	defer gm.Mu.Unlock()
+
+	var from *Room
+	var player *Player
+	for _, room := range gm.Rooms {
+		for _, p := range room.Players {
+			if p.ID == playerID {
+				from, player = room, p
+			}
+		}
+	}
+	if player == nil {
+		return nil, ErrPlayerNotFound
+	}
+
+	to, ok := gm.Rooms[targetRoomID]
+	switch {
+	case !ok:
+		return nil, ErrRoomNotFound
+	case to == from:
+		return nil, ErrSameRoom
+	case from.Started || to.Started:
+		return nil, ErrRoomStarted
+	case len(to.Players)+len(to.SavedPlayers) >= to.Settings.Seats():
+		return nil, ErrRoomFull
+	}
+
+	from.RemovePlayer(player)
+
+	player.Index = to.FreeSeat()
+	player.Team = TeamForSeat(player.Index)
+	player.Hand = []Card{}
+	player.Ready = false
+	to.Players = append(to.Players, player)
+	to.Track(player)
+	to.SortPlayers()
+	to.Game.Seat(player)
+	to.UpdateBelowFull(time.Now())
+
+	return from, nil
+}
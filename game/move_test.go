@@ -0,0 +1,83 @@
+package game
+
+import "testing"
+
+func TestSeatHandsOverTheTrumpPlayerAndTrickPlace(t *testing.T) {
+	g := seatedGame(RoomSettings{TableSize: 4})
+	old := g.Players[2]
+	g.TrumpPlayer = old
+	g.TrickPlayOrder = []*Player{g.Players[1], old}
+
+	replacement := &Player{ID: old.ID, Index: old.Index, Team: old.Team}
+	g.Seat(replacement)
+
+	if g.Players[2] != replacement {
+		t.Error("the replacement didn't take over the seat")
+	}
+	if g.TrumpPlayer != replacement {
+		t.Error("the Trump Player still points at the player who left")
+	}
+	if g.TrickPlayOrder[1] != replacement {
+		t.Error("the current trick still points at the player who left")
+	}
+}
+
+// lobby is a room of the manager that hasn't started, with a player in each of the seats
+func lobby(gm *GameManager, ids ...string) *Room {
+	room := NewRoom(RoomSettings{TableSize: 4})
+	for seat, id := range ids {
+		p := &Player{ID: id, Index: seat, Team: TeamForSeat(seat), Ready: true}
+		room.Players = append(room.Players, p)
+		room.Game.Seat(p)
+	}
+	gm.Rooms[room.ID] = room
+	return room
+}
+
+func TestMovePlayerBetweenLobbies(t *testing.T) {
+	gm := &GameManager{Rooms: make(map[string]*Room)}
+	from := lobby(gm, "a", "b")
+	to := lobby(gm, "c")
+
+	left, err := gm.MovePlayer("a", to.ID)
+	if err != nil || left != from {
+		t.Fatalf("MovePlayer() = %v, %v; want the room it left", left, err)
+	}
+	if len(from.Players) != 1 || len(from.Game.Players) != 1 || from.Players[0].ID != "b" {
+		t.Fatalf("room left holds %d players, %d seats; want only b", len(from.Players), len(from.Game.Players))
+	}
+	if len(to.Players) != 2 || len(to.Game.Players) != 2 {
+		t.Fatalf("target room holds %d players, %d seats; want 2", len(to.Players), len(to.Game.Players))
+	}
+	moved := to.Players[1]
+	if moved.ID != "a" || moved.Index != 1 || moved.Team != TeamForSeat(1) || moved.Ready {
+		t.Fatalf("moved player %+v, want a in the free seat 1 and not ready", moved)
+	}
+}
+
+func TestMovePlayerRejected(t *testing.T) {
+	gm := &GameManager{Rooms: make(map[string]*Room)}
+	from := lobby(gm, "a")
+	full := lobby(gm, "b", "c", "d", "e")
+	started := lobby(gm, "f")
+	started.Started = true
+
+	tests := []struct {
+		playerID, roomID string
+		want             error
+	}{
+		{"nobody", full.ID, ErrPlayerNotFound},
+		{"a", "missing", ErrRoomNotFound},
+		{"a", from.ID, ErrSameRoom},
+		{"a", started.ID, ErrRoomStarted},
+		{"a", full.ID, ErrRoomFull},
+	}
+	for _, tt := range tests {
+		if _, err := gm.MovePlayer(tt.playerID, tt.roomID); err != tt.want {
+			t.Errorf("MovePlayer(%s, %s) = %v, want %v", tt.playerID, tt.roomID, err, tt.want)
+		}
+	}
+	if len(from.Players) != 1 {
+		t.Fatalf("a rejected move took the player out of their room")
+	}
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Phase is a step of a game's lifecycle. Actions are only accepted in the phase
+// they belong to, and the game only moves between phases through Transition.
+type Phase string
+
+const (
+	PhaseLobby        Phase = "lobby"         // Waiting for players or for the first deal
+	PhaseDealing      Phase = "dealing"       // Cards are going out: the Trump Player's first ones, or the rest once trump is set
+	PhaseWaitingTrump Phase = "waiting_trump" // Hands are out, the Trump Player picks trump
+	PhasePlaying      Phase = "playing"       // Trump is set, tricks are being played
+	PhaseRoundOver    Phase = "round_over"    // A team took the Round, the next deal is pending
+	PhaseMatchOver    Phase = "match_over"    // A team won the match, by score or forfeit
+	PhasePaused       Phase = "paused"        // Play stopped until a seat is filled; see Pause
+)
+
+// ErrIllegalTransition is returned by Transition for a move the lifecycle doesn't allow
+var ErrIllegalTransition = errors.New("illegal phase transition")
+
+// phaseTransitions lists the phases each phase may move on to. Pausing and
+// resuming aren't listed: Pause and Resume handle them.
+var phaseTransitions = map[Phase][]Phase{
+	PhaseLobby:        {PhaseDealing},
+	PhaseDealing:      {PhaseLobby, PhaseWaitingTrump, PhasePlaying, PhaseMatchOver},
+	PhaseWaitingTrump: {PhaseDealing, PhaseMatchOver},
+	PhasePlaying:      {PhaseRoundOver, PhaseMatchOver},
+	PhaseRoundOver:    {PhaseWaitingTrump, PhaseMatchOver},
+	PhasePaused:       {PhaseMatchOver},
+	PhaseMatchOver:    {},
+}
+
+// CanTransition reports whether the lifecycle allows moving from one phase to another
+func CanTransition(from, to Phase) bool {
+	for _, next := range phaseTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the game to the next phase of its lifecycle, or returns
+// ErrIllegalTransition. A paused game stays paused: a legal move from the phase
+// it was paused in (e.g. a deal finishing meanwhile) becomes the phase it resumes in.
+func (g *Game) Transition(to Phase) error {
+	from := g.Phase
+	if from == PhasePaused && to != PhaseMatchOver {
+		if !CanTransition(g.PausedFrom, to) {
+			return fmt.Errorf("%w: paused %s to %s", ErrIllegalTransition, g.PausedFrom, to)
+		}
+		g.PausedFrom = to
+		return nil
+	}
+
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrIllegalTransition, from, to)
+	}
+	g.Phase = to
+	return nil
+}
+
+// Pause stops play until Resume. A game already paused or over stays as it is.
+func (g *Game) Pause() {
+	if g.Phase == PhasePaused || g.Phase == PhaseMatchOver {
+		return
+	}
+	g.PausedFrom = g.Phase
+	g.Phase = PhasePaused
+}
+
+// Resume puts a paused game back in the phase it was paused in
+func (g *Game) Resume() {
+	if g.Phase != PhasePaused {
+		return
+	}
+	g.Phase = g.PausedFrom
+	g.PausedFrom = ""
+}
+
+// Halted reports whether play is stopped, paused or for good
+func (g *Game) Halted() bool {
+	return g.Phase == PhasePaused || g.Phase == PhaseMatchOver
+}
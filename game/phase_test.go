@@ -0,0 +1,51 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransitionFollowsTheLifecycle(t *testing.T) {
+	tests := []struct {
+		from, to Phase
+		ok       bool
+	}{
+		{PhaseLobby, PhaseDealing, true},
+		{PhaseLobby, PhasePlaying, false},
+		{PhaseDealing, PhaseLobby, true},
+		{PhaseWaitingTrump, PhaseDealing, true},
+		{PhaseWaitingTrump, PhasePlaying, false}, // The rest of the hands go out first
+		{PhaseDealing, PhasePlaying, true},
+		{PhasePlaying, PhaseRoundOver, true},
+		{PhaseMatchOver, PhaseLobby, false},
+	}
+	for _, tt := range tests {
+		g := &Game{Phase: tt.from}
+		err := g.Transition(tt.to)
+		if tt.ok {
+			if err != nil || g.Phase != tt.to {
+				t.Errorf("%s to %s: got %v in phase %s, want it allowed", tt.from, tt.to, err, g.Phase)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrIllegalTransition) || g.Phase != tt.from {
+			t.Errorf("%s to %s: got %v in phase %s, want ErrIllegalTransition", tt.from, tt.to, err, g.Phase)
+		}
+	}
+}
+
+func TestTransitionWhilePausedChangesThePhaseToResumeIn(t *testing.T) {
+	g := &Game{Phase: PhaseDealing}
+	g.Pause()
+
+	if err := g.Transition(PhasePlaying); err != nil {
+		t.Fatalf("Transition(playing) while paused mid-deal = %v", err)
+	}
+	if err := g.Transition(PhaseLobby); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("Transition(lobby) while paused in playing = %v, want ErrIllegalTransition", err)
+	}
+	g.Resume()
+	if g.Phase != PhasePlaying {
+		t.Fatalf("resumed in %s, want %s", g.Phase, PhasePlaying)
+	}
+}
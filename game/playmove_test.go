@@ -0,0 +1,32 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlayMoveIgnoresAResentMove(t *testing.T) {
+	led := cards(t, CardOrderAceHigh, [2]string{"K", "hearts"})
+	g := botGame(t, CardOrderAceHigh, 1, led...)
+	hand := cards(t, CardOrderAceHigh, [2]string{"2", "hearts"}, [2]string{"3", "hearts"})
+	g.Players[1].Hand = hand
+
+	if err := g.PlayMove("b", "m-1", hand[0]); err != nil {
+		t.Fatalf("PlayMove() error = %v", err)
+	}
+	if err := g.PlayMove("b", "m-1", hand[0]); !errors.Is(err, ErrDuplicateMove) {
+		t.Fatalf("resent PlayMove() error = %v, want %v", err, ErrDuplicateMove)
+	}
+	if len(g.CurrentTrick) != 2 || g.CurrentPlayerID != "c" {
+		t.Fatalf("trick %v with %q to play after the resend, want the first play alone to count", g.CurrentTrick, g.CurrentPlayerID)
+	}
+
+	// The same move_id can't stand for another card
+	if err := g.PlayMove("b", "m-1", hand[1]); err == nil || errors.Is(err, ErrDuplicateMove) {
+		t.Fatalf("PlayMove() reusing m-1 for %v error = %v, want a rejection", hand[1], err)
+	}
+	// Without a move_id the resend is an ordinary out-of-turn play
+	if err := g.PlayMove("b", "", hand[0]); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("PlayMove() without a move_id error = %v, want %v", err, ErrNotYourTurn)
+	}
+}
@@ -0,0 +1,43 @@
+package game
+
+// EnqueueLocked adds the player to the back of the matchmaking queue. Once seats
+// players are waiting, the longest waiting ones are taken off the queue and returned
+// in the order they queued. The caller must hold Mu.
+func (gm *GameManager) EnqueueLocked(player *Player, seats int) []*Player {
+	for _, p := range gm.Queue {
+		if p.ID == player.ID {
+			return nil
+		}
+	}
+	gm.Queue = append(gm.Queue, player)
+
+	if len(gm.Queue) < seats {
+		return nil
+	}
+	table := append([]*Player{}, gm.Queue[:seats]...)
+	gm.Queue = append([]*Player{}, gm.Queue[seats:]...)
+	return table
+}
+
+// DequeueLocked takes the player out of the matchmaking queue and reports whether
+// they were in it. The caller must hold Mu.
+func (gm *GameManager) DequeueLocked(playerID string) bool {
+	for i, p := range gm.Queue {
+		if p.ID == playerID {
+			gm.Queue = append(gm.Queue[:i], gm.Queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// QueuePositionLocked returns the player's 1-based place in the matchmaking queue,
+// or 0 if they aren't queued. The caller must hold Mu.
+func (gm *GameManager) QueuePositionLocked(playerID string) int {
+	for i, p := range gm.Queue {
+		if p.ID == playerID {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package game
+
+// GameState is the state of a match right after one move of its replay log
+type GameState struct {
+	Step         int            `json:"step"` // Index of Move in the log
+	Move         MoveRecord     `json:"move"`
+	Round        int            `json:"round"` // 1-based, counted by trump choices
+	TrumpSuit    string         `json:"trump_suit"`
+	CurrentTrick TrickRecord    `json:"current_trick"` // WinnerID is set once the trick is complete
+	Tricks       map[string]int `json:"tricks"`        // Tricks each team took this Round
+	PlayerTricks map[string]int `json:"player_tricks"` // Tricks each player took over the match
+	RoundScores  map[string]int `json:"round_scores"`  // Points each team has won over the match
+}
+
+// ReplayGame rebuilds a match from its replay log and returns the state after every
+// move, in order. It only reads the moves, so it's safe on any stored log.
+//
+// The log doesn't hold the table size, so it's taken from the players who played a
+// card. A Round is scored with the room's rules once the next trump is chosen or the
+// log ends; games saved before their rules were kept pass the zero ScoringRules and
+// are scored with DefaultScoringRules. A forfeit gives the match to the other team
+// the way forfeiting does live, and the Round it cut short isn't scored. Team tallies
+// need the team recorded with each move; older logs without it only replay the
+// per-player tricks.
+func ReplayGame(moves []MoveRecord, rules ScoringRules) []GameState {
+	if rules.Validate() != nil {
+		rules = DefaultScoringRules()
+	}
+
+	seats := make(map[string]bool)
+	for _, m := range moves {
+		if m.Action == "play_card" {
+			seats[m.PlayerID] = true
+		}
+	}
+
+	var (
+		states       []GameState
+		round        int
+		trumpSuit    string
+		trumpTeam    string
+		trick        TrickRecord
+		forfeited    bool
+		teams        = make(map[string]string)
+		tricks       = make(map[string]int)
+		playerTricks = make(map[string]int)
+		roundScores  = make(map[string]int)
+	)
+
+	scoreRound := func() {
+		winner, loser := "team1", "team2"
+		// A tied Round goes against the Trump team, as in IsRoundOver
+		if tricks[loser] > tricks[winner] || (tricks[loser] == tricks[winner] && trumpTeam == winner) {
+			winner, loser = loser, winner
+		}
+		if tricks[winner] == 0 {
+			return
+		}
+		roundScores[winner] += rules.RoundPoints(tricks[loser], winner == trumpTeam)
+	}
+
+	for i, m := range moves {
+		if m.Team != "" {
+			teams[m.PlayerID] = m.Team
+		}
+
+		switch m.Action {
+		case "choose_trump":
+			scoreRound()
+			round++
+			trumpSuit = m.TrumpSuit
+			trumpTeam = m.Team
+			trick = TrickRecord{}
+			tricks = make(map[string]int)
+		case "play_card":
+			// A complete trick stays in the state until the next card is led
+			if trick.WinnerID != "" {
+				trick = TrickRecord{}
+			}
+			if m.Card != nil {
+				trick.Cards = append(trick.Cards, *m.Card)
+				trick.PlayerIDs = append(trick.PlayerIDs, m.PlayerID)
+			}
+			if len(trick.Cards) == len(seats) {
+				g := Game{TrumpSuit: trumpSuit, CurrentTrick: trick.Cards}
+				trick.WinnerID = trick.PlayerIDs[g.winningIndex()]
+				playerTricks[trick.WinnerID]++
+				if team := teams[trick.WinnerID]; team != "" {
+					tricks[team]++
+				}
+			}
+		case "forfeit":
+			forfeited = true
+			winner := "team1"
+			if m.Team == winner {
+				winner = "team2"
+			}
+			roundScores[winner] = TargetScore
+		}
+
+		if i == len(moves)-1 && !forfeited {
+			scoreRound()
+		}
+
+		states = append(states, GameState{
+			Step:      i,
+			Move:      m,
+			Round:     round,
+			TrumpSuit: trumpSuit,
+			CurrentTrick: TrickRecord{
+				Cards:     cloneCards(trick.Cards),
+				PlayerIDs: append([]string(nil), trick.PlayerIDs...),
+				WinnerID:  trick.WinnerID,
+			},
+			Tricks:       cloneCounts(tricks),
+			PlayerTricks: cloneCounts(playerTricks),
+			RoundScores:  cloneCounts(roundScores),
+		})
+	}
+	return states
+}
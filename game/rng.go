@@ -0,0 +1,41 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RNG is the source of randomness for shuffles, room IDs and bot choices
+type RNG interface {
+	Intn(n int) int
+	Shuffle(n int, swap func(i, j int))
+}
+
+// Random is the RNG used throughout the game. It's a variable so tests can
+// swap in a seeded source (e.g. game.Random = game.NewRNG(1)) for reproducible deals.
+var Random RNG = NewRNG(time.Now().UnixNano())
+
+// lockedRNG guards a *rand.Rand, which isn't safe for concurrent use, since
+// every room shuffles from its own goroutine
+type lockedRNG struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewRNG returns a goroutine-safe RNG seeded with seed
+func NewRNG(seed int64) RNG {
+	return &lockedRNG{r: rand.New(rand.NewSource(seed))}
+}
+
+func (l *lockedRNG) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+func (l *lockedRNG) Shuffle(n int, swap func(i, j int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.r.Shuffle(n, swap)
+}
@@ -0,0 +1,113 @@
+package game
+
+import "testing"
+
+// seatedGame returns a game of the room's size with every seat taken, hands empty
+func seatedGame(settings RoomSettings) *Game {
+	g := newGameFor(settings)
+	for seat := 0; seat < settings.Seats(); seat++ {
+		g.Seat(&Player{ID: string(rune('a' + seat)), Index: seat, Team: TeamForSeat(seat)})
+	}
+	return g
+}
+
+func TestIsRoundOverTiedStrippedRoundGoesAgainstTrumpTeam(t *testing.T) {
+	g := seatedGame(RoomSettings{DeckVariant: DeckStripped, TableSize: 4})
+	if g.TricksToWinRound != 5 {
+		t.Fatalf("TricksToWinRound = %d, want 5 for 8-card hands", g.TricksToWinRound)
+	}
+	g.TrumpPlayer = g.Players[0] // team2
+	g.Scores = map[string]int{"team1": 4, "team2": 4}
+
+	over, winner := g.IsRoundOver()
+	if !over || winner != "team1" {
+		t.Fatalf("IsRoundOver() = %v, %q; want true, team1 (the team opposing the Trump Player)", over, winner)
+	}
+}
+
+func TestIsRoundOverWaitsForTheLastCard(t *testing.T) {
+	g := seatedGame(RoomSettings{DeckVariant: DeckStripped, TableSize: 4})
+	g.TrumpPlayer = g.Players[0]
+	g.Scores = map[string]int{"team1": 4, "team2": 3}
+	g.Players[1].Hand = []Card{{Suit: "hearts", Rank: "A", Value: 14}}
+
+	if over, _ := g.IsRoundOver(); over {
+		t.Fatal("the Round ended while a card was still in hand")
+	}
+}
+
+func TestIsRoundOverTargetReached(t *testing.T) {
+	g := seatedGame(RoomSettings{DeckVariant: DeckStripped, TableSize: 4})
+	g.TrumpPlayer = g.Players[0]
+	g.Scores = map[string]int{"team1": 0, "team2": 5}
+	g.Players[1].Hand = []Card{{Suit: "hearts", Rank: "A", Value: 14}}
+
+	if over, winner := g.IsRoundOver(); !over || winner != "team2" {
+		t.Fatalf("IsRoundOver() = %v, %q; want true, team2", over, winner)
+	}
+}
+
+func TestIsRoundOverNotBeforeAnyTrick(t *testing.T) {
+	g := seatedGame(RoomSettings{TableSize: 4})
+	if over, _ := g.IsRoundOver(); over {
+		t.Fatal("a Round with no tricks played and empty hands counted as over")
+	}
+}
+
+func TestIsRoundOverScores(t *testing.T) {
+	tests := []struct {
+		name     string
+		scores   map[string]int
+		cardLeft bool
+		over     bool
+		winner   string
+	}{
+		{"6-6 with a trick to play", map[string]int{"team1": 6, "team2": 6}, true, false, ""},
+		{"7-0", map[string]int{"team1": 7, "team2": 0}, true, true, "team1"},
+		{"0-7", map[string]int{"team1": 0, "team2": 7}, true, true, "team2"},
+		{"exactly 7 against 6", map[string]int{"team1": 6, "team2": 7}, false, true, "team2"},
+		{"one short of 7", map[string]int{"team1": 6, "team2": 5}, true, false, ""},
+		{"no tricks", map[string]int{}, true, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := seatedGame(RoomSettings{TableSize: 4})
+			g.TrumpPlayer = g.Players[1]
+			g.Scores = tt.scores
+			if tt.cardLeft {
+				g.Players[2].Hand = []Card{{Suit: "clubs", Rank: "2", Value: 2}}
+			}
+
+			over, winner := g.IsRoundOver()
+			if over != tt.over || winner != tt.winner {
+				t.Fatalf("IsRoundOver() = %v, %q; want %v, %q", over, winner, tt.over, tt.winner)
+			}
+		})
+	}
+}
+
+func TestIsMatchOverScores(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores map[string]int
+		over   bool
+		winner string
+	}{
+		{"6-6", map[string]int{"team1": 6, "team2": 6}, false, ""},
+		{"7-0", map[string]int{"team1": 7, "team2": 0}, true, "team1"},
+		{"exactly at target", map[string]int{"team1": 4, "team2": TargetScore}, true, "team2"},
+		{"past target after a Kot", map[string]int{"team1": 5, "team2": 9}, true, "team2"},
+		{"no Rounds played", map[string]int{}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := seatedGame(RoomSettings{TableSize: 4})
+			g.RoundScores = tt.scores
+
+			over, winner := g.IsMatchOver()
+			if over != tt.over || winner != tt.winner {
+				t.Fatalf("IsMatchOver() = %v, %q; want %v, %q", over, winner, tt.over, tt.winner)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultRoomSettingsBoundsTheReconnectTimeout(t *testing.T) {
+	tests := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", DefaultReconnectTimeout},
+		{"45s", 45 * time.Second},
+		{"10s", MinReconnectTimeout},
+		{"2m", MaxReconnectTimeout},
+		{"5s", DefaultReconnectTimeout},
+		{"10m", DefaultReconnectTimeout},
+	}
+	for _, tt := range tests {
+		t.Setenv("RECONNECT_TIMEOUT", tt.env)
+		if got := DefaultRoomSettings().ReconnectWait(); got != tt.want {
+			t.Errorf("RECONNECT_TIMEOUT=%q holds seats for %s, want %s", tt.env, got, tt.want)
+		}
+	}
+
+	if got := (RoomSettings{}).ReconnectWait(); got != DefaultReconnectTimeout {
+		t.Errorf("unset ReconnectWait() = %s, want %s", got, DefaultReconnectTimeout)
+	}
+}
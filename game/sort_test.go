@@ -0,0 +1,29 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortHand(t *testing.T) {
+	hand := cards(t, CardOrderAceHigh,
+		[2]string{"3", "diamonds"}, [2]string{"K", "hearts"}, [2]string{"A", "clubs"},
+		[2]string{"10", "spades"}, [2]string{"A", "hearts"}, [2]string{"2", "spades"})
+
+	tests := []struct {
+		trump string
+		want  [][2]string
+	}{
+		{"", [][2]string{{"10", "spades"}, {"2", "spades"}, {"A", "hearts"}, {"K", "hearts"}, {"A", "clubs"}, {"3", "diamonds"}}},
+		{"diamonds", [][2]string{{"3", "diamonds"}, {"10", "spades"}, {"2", "spades"}, {"A", "hearts"}, {"K", "hearts"}, {"A", "clubs"}}},
+	}
+	for _, tt := range tests {
+		want := cards(t, CardOrderAceHigh, tt.want...)
+		if got := SortHand(hand, tt.trump); !reflect.DeepEqual(got, want) {
+			t.Errorf("SortHand(%q) = %v, want %v", tt.trump, got, want)
+		}
+	}
+	if hand[0].Suit != "diamonds" {
+		t.Error("SortHand reordered the hand it was given")
+	}
+}
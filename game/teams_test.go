@@ -0,0 +1,34 @@
+package game
+
+import "testing"
+
+func TestValidateTeamsMovesPlayersToTheTeamOfTheirSeat(t *testing.T) {
+	room := &Room{Players: []*Player{
+		{ID: "a", Index: 0, Team: "team2"},
+		{ID: "b", Index: 1, Team: "team2"},
+		{ID: "c", Index: 2, Team: "team2"},
+		{ID: "d", Index: 3, Team: "team1"},
+	}}
+
+	if err := room.ValidateTeams(); err != nil {
+		t.Fatalf("ValidateTeams() = %v, want the 3-1 split corrected", err)
+	}
+	for _, p := range room.Players {
+		if want := TeamForSeat(p.Index); p.Team != want {
+			t.Errorf("player %s in seat %d is on %s, want %s", p.ID, p.Index, p.Team, want)
+		}
+	}
+}
+
+func TestValidateTeamsRejectsASharedSeat(t *testing.T) {
+	room := &Room{Players: []*Player{
+		{ID: "a", Index: 0, Team: TeamForSeat(0)},
+		{ID: "b", Index: 1, Team: TeamForSeat(1)},
+		{ID: "c", Index: 1, Team: TeamForSeat(1)},
+		{ID: "d", Index: 3, Team: TeamForSeat(3)},
+	}}
+
+	if err := room.ValidateTeams(); err == nil {
+		t.Fatal("ValidateTeams() = nil with two players in seat 1")
+	}
+}
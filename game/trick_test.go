@@ -0,0 +1,128 @@
+package game
+
+import "testing"
+
+// fullTrick is a 4-card trick led by seat 0 in hearts, with seat 2 trumping in spades
+func fullTrick(t *testing.T) *Game {
+	t.Helper()
+	trick := cards(t, CardOrderAceHigh, [2]string{"K", "hearts"}, [2]string{"A", "hearts"}, [2]string{"2", "spades"}, [2]string{"Q", "hearts"})
+	return botGame(t, CardOrderAceHigh, 0, trick...)
+}
+
+func TestDetermineTrickWinnerTrumpTakesTheTrick(t *testing.T) {
+	g := fullTrick(t)
+
+	winner, err := g.DetermineTrickWinner(g.Players)
+	if err != nil {
+		t.Fatalf("DetermineTrickWinner() error = %v", err)
+	}
+	if want := g.Players[2].ID; winner != want {
+		t.Fatalf("DetermineTrickWinner() = %q, want %q who trumped", winner, want)
+	}
+}
+
+func TestDetermineTrickWinnerFollowsTheCardOrder(t *testing.T) {
+	tests := []struct {
+		order string
+		want  string
+	}{
+		{CardOrderAceHigh, "a"}, // The Ace takes the trick
+		{CardOrderAceLow, "b"},  // The Ace loses to the King
+	}
+	for _, tt := range tests {
+		trick := cards(t, tt.order, [2]string{"A", "hearts"}, [2]string{"K", "hearts"}, [2]string{"2", "hearts"}, [2]string{"Q", "hearts"})
+		g := botGame(t, tt.order, 0, trick...)
+
+		winner, err := g.DetermineTrickWinner(g.Players)
+		if err != nil {
+			t.Fatalf("%s: DetermineTrickWinner() error = %v", tt.order, err)
+		}
+		if winner != tt.want {
+			t.Errorf("%s: DetermineTrickWinner() = %q, want %q", tt.order, winner, tt.want)
+		}
+	}
+}
+
+func TestDetermineTrickWinnerRejectsAMismatchedPlayOrder(t *testing.T) {
+	g := fullTrick(t)
+	g.TrickPlayOrder = g.TrickPlayOrder[:3]
+
+	if winner, err := g.DetermineTrickWinner(g.Players); err == nil || winner != "" {
+		t.Fatalf("DetermineTrickWinner() = %q, %v; want an error for 4 cards and 3 plays", winner, err)
+	}
+
+	g.CurrentTrick, g.TrickPlayOrder = nil, nil
+	if _, err := g.DetermineTrickWinner(g.Players); err == nil {
+		t.Fatal("DetermineTrickWinner() = nil error for an empty trick")
+	}
+}
+
+func TestTheTrumpPlayerLeadsTheFirstTrickAndWinnersTheRest(t *testing.T) {
+	hand := cards(t, CardOrderAceHigh, [2]string{"3", "clubs"}, [2]string{"4", "clubs"}, [2]string{"5", "clubs"}, [2]string{"6", "clubs"})
+	deal := func(g *Game) {
+		for i, p := range g.Players {
+			p.Hand = []Card{hand[i]}
+		}
+	}
+
+	g := botGame(t, CardOrderAceHigh, 0)
+	g.TrumpPlayer = g.Players[1]
+	deal(g)
+	if !g.SetRoundLeader() || g.CurrentPlayerID != g.TrumpPlayer.ID {
+		t.Fatalf("SetRoundLeader() left the lead with %q, want the Trump Player %q", g.CurrentPlayerID, g.TrumpPlayer.ID)
+	}
+	g.CurrentPlayerID = g.Players[0].ID
+	if err := g.PlayCard(g.Players[0].ID, hand[0]); err == nil {
+		t.Fatal("seat 0 led the first trick instead of the Trump Player")
+	}
+	g.SetRoundLeader()
+	if err := g.PlayCard(g.TrumpPlayer.ID, hand[1]); err != nil {
+		t.Fatalf("the Trump Player couldn't lead: %v", err)
+	}
+
+	// Seat 2 trumps the first trick, led by seat 0, and leads the next one
+	g = fullTrick(t)
+	g.TrumpPlayer = g.Players[0]
+	winner, err := g.DetermineTrickWinner(g.Players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.RecordTrick(winner)
+	g.ResetTrick()
+	deal(g)
+	if !g.SetTrickLeader(winner) || g.CurrentPlayerID != winner {
+		t.Fatalf("SetTrickLeader(%q) left the lead with %q", winner, g.CurrentPlayerID)
+	}
+	g.CurrentPlayerID = g.TrumpPlayer.ID
+	if err := g.PlayCard(g.TrumpPlayer.ID, hand[0]); err == nil {
+		t.Fatal("the Trump Player led the second trick instead of its winner")
+	}
+	g.SetTrickLeader(winner)
+	if err := g.PlayCard(winner, hand[2]); err != nil {
+		t.Fatalf("the trick winner %s couldn't lead: %v", winner, err)
+	}
+	if g.SetTrickLeader("nobody") {
+		t.Fatal("SetTrickLeader() gave the lead to a player who isn't seated")
+	}
+}
+
+func TestAuditTrickFlagsAPlayerWhoDidntFollowSuit(t *testing.T) {
+	g := fullTrick(t)
+	g.Players[2].Hand = cards(t, CardOrderAceHigh, [2]string{"3", "clubs"})
+	if reneges := g.AuditTrick(); len(reneges) != 0 {
+		t.Fatalf("AuditTrick() = %v for a legal trick", reneges)
+	}
+
+	// Seat 2 trumped while still holding a heart
+	g.Players[2].Hand = cards(t, CardOrderAceHigh, [2]string{"5", "hearts"})
+	reneges := g.AuditTrick()
+	want := Renege{PlayerID: g.Players[2].ID, Card: g.CurrentTrick[2], LeadSuit: "hearts"}
+	if len(reneges) != 1 || reneges[0] != want {
+		t.Fatalf("AuditTrick() = %v, want %v", reneges, want)
+	}
+
+	g.RecordRenege(reneges[0])
+	if last := g.Moves[len(g.Moves)-1]; last.Action != "renege_detected" || last.PlayerID != want.PlayerID || *last.Card != want.Card {
+		t.Fatalf("last move %+v, want the renege of %s flagged", last, want.PlayerID)
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUndoLastPlayGivesTheCardAndTurnBack(t *testing.T) {
+	played := cards(t, CardOrderAceHigh, [2]string{"K", "hearts"}, [2]string{"2", "hearts"})
+	g := botGame(t, CardOrderAceHigh, 2, played...)
+	g.LastPlayAt = time.Now()
+	g.Moves = []MoveRecord{{Action: "play_card", PlayerID: "a"}, {Action: "play_card", PlayerID: "b"}}
+
+	card, err := g.UndoLastPlay("b")
+	if err != nil {
+		t.Fatalf("UndoLastPlay() error = %v", err)
+	}
+	if card != played[1] || len(g.CurrentTrick) != 1 || len(g.TrickPlayOrder) != 1 {
+		t.Fatalf("UndoLastPlay() = %v leaving trick %v, want %v taken off", card, g.CurrentTrick, played[1])
+	}
+	if hand := g.Players[1].Hand; len(hand) != 1 || hand[0] != played[1] {
+		t.Errorf("hand after the undo = %v, want %v back", hand, played[1])
+	}
+	if g.CurrentPlayerID != "b" {
+		t.Errorf("turn after the undo is %q, want b's", g.CurrentPlayerID)
+	}
+	if len(g.Moves) != 1 {
+		t.Errorf("%d moves after the undo, want the undone play dropped", len(g.Moves))
+	}
+}
+
+func TestUndoLastPlayRejected(t *testing.T) {
+	played := cards(t, CardOrderAceHigh, [2]string{"K", "hearts"}, [2]string{"2", "hearts"})
+	tests := []struct {
+		name     string
+		trick    []Card
+		playerID string
+		playedAt time.Time
+	}{
+		{"someone played after", played, "a", time.Now()},
+		{"window passed", played, "b", time.Now().Add(-UndoWindow - time.Second)},
+		{"empty trick", nil, "a", time.Now()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := botGame(t, CardOrderAceHigh, len(tt.trick), tt.trick...)
+			g.LastPlayAt = tt.playedAt
+
+			if card, err := g.UndoLastPlay(tt.playerID); err == nil {
+				t.Fatalf("UndoLastPlay(%q) took back %v", tt.playerID, card)
+			}
+			if len(g.CurrentTrick) != len(tt.trick) {
+				t.Fatalf("trick is %v after a rejected undo, want %v", g.CurrentTrick, tt.trick)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import "hokm-backend/game"
+
+const MessageAck = "ack"
+
+// sendAck confirms to the player that their action was accepted, echoing the
+// message's correlation id. Messages without an id aren't acknowledged.
+func sendAck(player *game.Player, msg game.WSMessage) {
+	if msg.ID == "" {
+		return
+	}
+	player.Send(game.WSResponse{
+		Type: MessageAck,
+		Payload: map[string]interface{}{
+			"action": msg.Action,
+			"id":     msg.ID,
+		},
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestAcceptedActionsAreAckedWithTheirID(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	var trumpID string
+	waitFor(t, "a Trump Player", func() bool {
+		if tb.room.Game.TrumpPlayer == nil {
+			return false
+		}
+		trumpID = tb.room.Game.TrumpPlayer.ID
+		return true
+	})
+	trumpPlayer := tb.client(t, trumpID)
+
+	trumpPlayer.sendMessage(t, map[string]interface{}{"action": "choose_trump", "id": "c-1", "data": "hearts"})
+	if ack := trumpPlayer.expect(t, MessageAck); ack["action"] != "choose_trump" || ack["id"] != "c-1" {
+		t.Fatalf("ack = %v, want choose_trump c-1", ack)
+	}
+	trumpPlayer.expect(t, "turn_update")
+	waitFor(t, "play to begin", func() bool { return tb.room.Game.Phase == game.PhasePlaying })
+
+	// Someone out of turn gets their error, not an ack
+	game.Manager.Mu.RLock()
+	var other *game.Player
+	for _, p := range tb.room.Players {
+		if p.ID != trumpID {
+			other = p
+		}
+	}
+	wrong := other.Hand[0]
+	lead := tb.firstLegal(tb.room.Game.CurrentPlayer())
+	game.Manager.Mu.RUnlock()
+	waiting := tb.client(t, other.ID)
+	waiting.sendMessage(t, map[string]interface{}{"action": "play_card", "id": "c-2", "data": wrong})
+	waiting.expect(t, MessageOutOfTurn)
+
+	trumpPlayer.sendMessage(t, map[string]interface{}{"action": "play_card", "id": "c-3", "data": lead})
+	if ack := trumpPlayer.expect(t, MessageAck); ack["action"] != "play_card" || ack["id"] != "c-3" {
+		t.Fatalf("ack = %v, want play_card c-3", ack)
+	}
+	// The ack comes before the broadcast of the play
+	trumpPlayer.expect(t, "game_update")
+	waiting.expect(t, "turn_update")
+	if waiting.received(MessageAck) {
+		t.Fatal("a rejected play was acked")
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"hokm-backend/game"
+	"hokm-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const MessageRoomTerminated = "room_terminated"
+
+// AdminListRooms returns the full internal state of every room, hands included
+func AdminListRooms(c *gin.Context) {
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+
+	rooms := make([]gin.H, 0, len(game.Manager.Rooms))
+	for _, room := range game.Manager.Rooms {
+		trumpPlayerID := ""
+		if room.Game.TrumpPlayer != nil {
+			trumpPlayerID = room.Game.TrumpPlayer.ID
+		}
+
+		rooms = append(rooms, gin.H{
+			"id":                room.ID,
+			"created_at":        room.CreatedAt,
+			"settings":          room.Settings,
+			"started":           room.Started,
+			"players":           room.Players,
+			"saved_players":     room.SavedPlayers,
+			"kick_votes":        room.KickVotes,
+			"trump_player_id":   trumpPlayerID,
+			"trump_suit":        room.Game.TrumpSuit,
+			"current_player_id": room.Game.CurrentPlayerID,
+			"current_trick":     room.Game.CurrentTrick,
+			"deck_size":         len(room.Game.Deck),
+			"tricks":            room.Game.Scores,
+			"rounds":            room.Game.RoundScores,
+			"current_round":     room.Game.CurrentRound,
+			"is_game_over":      room.Game.Halted(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rooms": rooms})
+}
+
+// AdminTerminateRoom force-ends a room, tells its players and closes their connections
+func AdminTerminateRoom(c *gin.Context) {
+	roomID := c.Param("id")
+
+	game.Manager.Mu.Lock()
+	room, ok := game.Manager.Rooms[roomID]
+	if ok {
+		delete(game.Manager.Rooms, roomID)
+		room.Game.Pause()
+	}
+	game.Manager.Mu.Unlock()
+
+	if !ok {
+		utils.RespondError(c, http.StatusNotFound, utils.CodeNotFound, "Room not found")
+		return
+	}
+
+	log.Printf("Room %s terminated by admin %s", roomID, c.GetString("username"))
+	closeRoom(room, MessageRoomTerminated, "The room was terminated by an administrator.", CloseTerminated)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Room terminated", "room_id": roomID})
+}
+
+type movePlayerRequest struct {
+	RoomID string `json:"room_id" binding:"required"`
+}
+
+// AdminMovePlayer moves a player waiting in a lobby to another room that hasn't started
+func AdminMovePlayer(c *gin.Context) {
+	var req movePlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	playerID := c.Param("id")
+	from, err := game.Manager.MovePlayer(playerID, req.RoomID)
+	switch {
+	case errors.Is(err, game.ErrPlayerNotFound), errors.Is(err, game.ErrRoomNotFound):
+		utils.RespondError(c, http.StatusNotFound, utils.CodeNotFound, err.Error())
+		return
+	case errors.Is(err, game.ErrRoomStarted):
+		utils.RespondError(c, http.StatusConflict, utils.CodeInGame, err.Error())
+		return
+	case err != nil:
+		utils.RespondError(c, http.StatusConflict, utils.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	log.Printf("Player %s moved from room %s to %s by admin %s", playerID, from.ID, req.RoomID, c.GetString("username"))
+	if to := game.Manager.GetRoom(req.RoomID); to != nil {
+		announceMove(playerID, from, to)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Player moved", "player_id": playerID, "room_id": req.RoomID})
+}
+
+// announceMove sends both rooms their new roster and the moved player the room they joined
+func announceMove(playerID string, from, to *game.Room) {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
+	broadcastRosterUpdate(from)
+	broadcastRosterUpdate(to)
+
+	for _, p := range to.Players {
+		if p.ID == playerID && p.Connected {
+			sendJoinMessage(p, to)
+		}
+	}
+
+	// Wait for everyone to be ready once the room is full
+	if len(to.Players) == to.Settings.Seats() && !to.Started {
+		broadcastWaitingForReady(to)
+		scheduleReadyTimeout(to)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"log"
+	"time"
+)
+
+const MessageBotSeated = "bot_seated"
+
+// seatBot hands a disconnected player's seat to a bot of the room's BotLevel. The bot
+// plays their hand from their next turn on, until they reconnect and take it back.
+func seatBot(room *game.Room, player *game.Player) {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
+	if indexOfPlayer(room.Players, player) == -1 || player.Connected {
+		return
+	}
+	// The seat is taken care of, so it isn't given up when the countdown runs out
+	cancelReconnectCountdown(player, room)
+	player.BotLevel = room.Settings.BotLevel
+	log.Printf("A %s bot plays the seat of %s in room %s", player.BotLevel, player.ID, room.ID)
+
+	broadcastBotSeated(room, player)
+	broadcastRosterUpdate(room)
+
+	// A bot on turn plays right away rather than when the player's timer runs out
+	if room.Game.CurrentPlayerID == player.ID {
+		armTurnTimer(room)
+	}
+}
+
+// seatBotInSavedSeat hands the seat a departed player left open to a bot of the
+// room's BotLevel, under the same player ID so the game's moves still line up, and
+// resumes the room once every seat is taken again.
+func seatBotInSavedSeat(room *game.Room, playerID string) {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
+	data, ok := room.SavedPlayers[playerID]
+	if !ok {
+		return
+	}
+	hand, stale := room.Game.ReconcileHand(data.PlayerID, data.Hand)
+	if len(stale) > 0 {
+		log.Printf("Dropped %d stale cards from the saved hand of %s in room %s: %v", len(stale), data.PlayerID, room.ID, stale)
+	}
+	bot := &game.Player{
+		ID:       data.PlayerID,
+		Name:     NameGenerator(),
+		Team:     data.Team,
+		Hand:     hand,
+		Index:    data.Index,
+		BotLevel: room.Settings.BotLevel,
+	}
+	room.Players = append(room.Players, bot)
+	room.Track(bot)
+	room.UpdateBelowFull(time.Now())
+	room.SortPlayers()
+	room.Game.Seat(bot)
+	delete(room.SavedPlayers, data.PlayerID)
+	log.Printf("A %s bot takes the open seat of %s in room %s", bot.BotLevel, bot.ID, room.ID)
+
+	broadcastBotSeated(room, bot)
+	broadcastRosterUpdate(room)
+
+	if len(room.Players) < room.Settings.Seats() {
+		return
+	}
+	if err := room.ValidateTeams(); err != nil {
+		log.Printf("Room %s stays paused: %v", room.ID, err)
+		return
+	}
+	room.Game.Resume()
+	broadcastGameStateAfterReplacement(room, bot)
+	broadcastTurnUpdate(room)
+}
+
+// broadcastBotSeated tells the connected players that a bot plays the player's seat
+func broadcastBotSeated(room *game.Room, player *game.Player) {
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageBotSeated,
+				Payload: map[string]interface{}{
+					"player_id": player.ID,
+					"bot_level": player.BotLevel,
+				},
+			})
+		}
+	}
+}
+
+// reclaimFromBot gives a reconnecting player their seat back from the bot playing
+// it. The caller must hold game.Manager.Mu.
+func reclaimFromBot(player *game.Player, room *game.Room) {
+	if player.BotLevel == "" {
+		return
+	}
+	log.Printf("%s takes their seat in room %s back from the bot", player.ID, room.ID)
+	player.BotLevel = ""
+	if room.Game.CurrentPlayerID == player.ID {
+		armTurnTimer(room)
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import "hokm-backend/game"
+
+// Close codes sent with forced disconnects so clients can tell why they were
+// dropped. RFC 6455 leaves 4000-4999 to applications.
+const (
+	CloseReplaced      = 4001 // Another connection took over the seat
+	CloseIdle          = 4002 // The room was dissolved, or the player sent nothing, for too long
+	CloseInvalidToken  = 4003 // The ?token= query parameter didn't verify
+	CloseKicked        = 4004 // The table voted the player out
+	CloseTerminated    = 4005 // An admin terminated the room
+	CloseAlreadyInGame = 4006 // The account already holds a seat in a room
+)
+
+// closeConn sends a close frame with the code and reason and closes the player's connection
+func closeConn(player *game.Player, code int, reason string) {
+	if player.Conn == nil {
+		return
+	}
+	closeSocket(player.Conn, code, reason)
+}
+
+// closeSocket closes the connection with the code and reason once what was already sent to it is written
+func closeSocket(conn *game.Conn, code int, reason string) {
+	conn.CloseWith(code, reason)
+}
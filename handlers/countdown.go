@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"log"
+	"math"
+	"time"
+)
+
+const (
+	MessageGameStarting       = "game_starting"
+	MessageGameStartCancelled = "game_start_cancelled"
+)
+
+// armStartCountdown marks the room as started and returns the context of its
+// countdown. The caller must hold game.Manager.Mu.
+func armStartCountdown(room *game.Room) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	room.Started = true
+	room.CancelStart = cancel
+	return ctx
+}
+
+// runStartCountdown broadcasts game_starting once a second for GAME_START_COUNTDOWN
+// (3s by default) so every client is ready, then deals. It stops early if
+// cancelStartCountdown is called.
+func runStartCountdown(ctx context.Context, room *game.Room) {
+	countdown := config.GetEnvDuration("GAME_START_COUNTDOWN", 3*time.Second)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for left := int(math.Ceil(countdown.Seconds())); left > 0; left-- {
+		broadcastGameStarting(room, left)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	game.Manager.Mu.Lock()
+	if ctx.Err() != nil {
+		game.Manager.Mu.Unlock()
+		return
+	}
+	room.CancelStart = nil
+	game.Manager.Mu.Unlock()
+
+	initializeGame(room)
+}
+
+// cancelStartCountdown aborts a running countdown and puts the room back to waiting for players
+func cancelStartCountdown(room *game.Room) {
+	if room == nil || room.CancelStart == nil {
+		return
+	}
+
+	log.Printf("Start of room %s cancelled", room.ID)
+	room.CancelStart()
+	room.CancelStart = nil
+	room.Started = false
+
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageGameStartCancelled,
+				Payload: map[string]interface{}{
+					"room_id": room.ID,
+					"message": "A player left. Waiting for players.",
+				},
+			})
+		}
+	}
+}
+
+// abortStart puts a room whose deal couldn't begin back to waiting, telling the players
+// why and showing them the seats as they are now. The caller must hold game.Manager.Mu.
+func abortStart(room *game.Room, reason string) {
+	log.Printf("Start of room %s aborted: %s", room.ID, reason)
+	room.Started = false
+
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageGameStartCancelled,
+				Payload: map[string]interface{}{
+					"room_id": room.ID,
+					"message": reason,
+				},
+			})
+		}
+	}
+	broadcastRosterUpdate(room)
+}
+
+// broadcastGameStarting tells the room how long until the deal, under game.Manager.Mu
+// since the countdown runs beside the joins and leaves that change the seats
+func broadcastGameStarting(room *game.Room, secondsLeft int) {
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageGameStarting,
+				Payload: map[string]interface{}{
+					"room_id":       room.ID,
+					"seconds_left":  secondsLeft,
+					"scoring_rules": room.Settings.Scoring,
+				},
+			})
+		}
+	}
+}
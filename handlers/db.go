@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"hokm-backend/config"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DefaultDBTimeout bounds the database calls of a request unless DB_TIMEOUT says otherwise
+const DefaultDBTimeout = 5 * time.Second
+
+// requestDB returns the database bound to the request's context with the DB timeout
+// applied, so a slow database can't pile up handlers. Call cancel when done.
+func requestDB(c *gin.Context) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), config.GetEnvDuration("DB_TIMEOUT", DefaultDBTimeout))
+	return models.DB.WithContext(ctx), cancel
+}
+
+// respondDBError answers 503 when the database call timed out or the request was
+// cancelled, and the given status, code and message for any other error
+func respondDBError(c *gin.Context, err error, status int, code, message string) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		utils.RespondError(c, http.StatusServiceUnavailable, utils.CodeUnavailable, "Database unavailable, try again later")
+		return
+	}
+	utils.RespondError(c, status, code, message)
+}
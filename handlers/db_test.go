@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hokm-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCancelledRequestGetsServiceUnavailable(t *testing.T) {
+	testDB(t)
+	router := gin.New()
+	router.POST("/login", Login)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username":"alice","password":"secret-pass"}`)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(rec, req)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(waitTimeout):
+		t.Fatal("login with a cancelled context hung")
+	}
+
+	var body struct {
+		Error utils.APIError `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || body.Error.Code != utils.CodeUnavailable {
+		t.Fatalf("status %d with %+v, want %d and %s", rec.Code, body.Error, http.StatusServiceUnavailable, utils.CodeUnavailable)
+	}
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+)
+
+func TestCardsCantBePlayedUntilTheDealIsDone(t *testing.T) {
+	fastGame(t)
+	DealBatchInterval = 200 * time.Millisecond
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+
+	var trumpID string
+	var card game.Card
+	game.Manager.Mu.RLock()
+	trumpID = tb.room.Game.TrumpPlayer.ID
+	card = tb.room.Game.TrumpPlayer.Hand[0]
+	game.Manager.Mu.RUnlock()
+
+	c := tb.client(t, trumpID)
+	c.send(t, "choose_trump", "hearts")
+	c.expect(t, "trump_suit_selected")
+	c.send(t, "play_card", map[string]interface{}{"suit": card.Suit, "rank": card.Rank})
+
+	var rejected utils.APIError
+	decode(t, c.expect(t, MessageError), &rejected)
+	if rejected.Code != utils.CodeWrongPhase {
+		t.Fatalf("play_card during the deal got %+v, want %s", rejected, utils.CodeWrongPhase)
+	}
+	if c.received("turn_update") {
+		t.Fatal("turn_update was sent before the last deal batch")
+	}
+
+	c.expect(t, "turn_update")
+	waitFor(t, "play to begin", func() bool { return tb.room.Game.Phase == game.PhasePlaying })
+	tb.play(t, tb.firstLegal)
+}
+
+func TestDisconnectBetweenDealBatches(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	DealBatchInterval = 200 * time.Millisecond
+	srv := newTestServer(t)
+
+	tb, tokens := joinAccountTable(t, srv, "dealt", 4)
+	tb.startGame(t)
+
+	game.Manager.Mu.RLock()
+	trumpID := tb.room.Game.TrumpPlayer.ID
+	game.Manager.Mu.RUnlock()
+	var leaverID, otherID string
+	for _, id := range tb.ids {
+		switch {
+		case id == trumpID:
+		case leaverID == "":
+			leaverID = id
+		default:
+			otherID = id
+		}
+	}
+
+	// The leaver drops right after the first batch
+	leaver := tb.client(t, leaverID)
+	tb.client(t, trumpID).send(t, "choose_trump", "hearts")
+	leaver.expect(t, "deal_cards_batch_1")
+	leaver.ws.Close()
+	waitFor(t, "the disconnect", func() bool {
+		for _, p := range tb.room.Players {
+			if p.ID == leaverID {
+				return !p.Connected
+			}
+		}
+		return false
+	})
+
+	// Everyone else is still dealt the whole hand and play begins
+	other := tb.client(t, otherID)
+	other.expect(t, "deal_cards_batch_2")
+	other.expect(t, "deal_cards_batch_3")
+	tb.client(t, trumpID).expect(t, "turn_update")
+	waitFor(t, "play to begin", func() bool { return tb.room.Game.Phase == game.PhasePlaying })
+
+	// The reconnect brings the full hand the leaver missed the end of
+	back := dial(t, srv, "token="+tokens[leaverID])
+	var state struct {
+		Hand []game.Card `json:"your_hand"`
+	}
+	decode(t, back.expect(t, MessageGameState), &state)
+	if len(state.Hand) != 13 {
+		t.Fatalf("the reconnect resent %d cards, want 13", len(state.Hand))
+	}
+	for _, typ := range back.types() {
+		if strings.HasPrefix(typ, "deal_cards_batch_") {
+			t.Fatalf("the reconnect got %s after the deal was over", typ)
+		}
+	}
+}
+
+func TestZeroDelayDealFillsEveryHand(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	start := time.Now()
+	tb.startGame(t)
+	tb.chooseTrump(t, "clubs")
+
+	// With the animation timing the deal alone takes over 13 seconds
+	if took := time.Since(start); took > 2*time.Second {
+		t.Fatalf("the deal took %v without delays", took)
+	}
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	for _, p := range tb.room.Game.Players {
+		if len(p.Hand) != 13 {
+			t.Errorf("seat %d holds %d cards, want 13", p.Index, len(p.Hand))
+		}
+	}
+	if len(tb.room.Game.Deck) != 0 {
+		t.Errorf("%d cards left in the deck after the deal", len(tb.room.Game.Deck))
+	}
+}
+
+func TestSecondChooseTrumpIsIgnored(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	game.Manager.Mu.RLock()
+	trumpID := tb.room.Game.TrumpPlayer.ID
+	hands := make(map[string][]game.Card)
+	for _, p := range tb.room.Game.Players {
+		hands[p.ID] = append([]game.Card(nil), p.Hand...)
+	}
+	game.Manager.Mu.RUnlock()
+
+	c := tb.client(t, trumpID)
+	c.send(t, "choose_trump", "spades")
+	var rejected utils.APIError
+	decode(t, c.expect(t, MessageError), &rejected)
+	if rejected.Code != utils.CodeWrongPhase {
+		t.Fatalf("second choose_trump got %+v, want %s", rejected, utils.CodeWrongPhase)
+	}
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	if suit := tb.room.Game.TrumpSuit; suit != "hearts" {
+		t.Fatalf("trump is %s after a second choose_trump, want hearts", suit)
+	}
+	for _, p := range tb.room.Game.Players {
+		if !reflect.DeepEqual(p.Hand, hands[p.ID]) {
+			t.Fatalf("seat %d was dealt again: %v, had %v", p.Index, p.Hand, hands[p.ID])
+		}
+	}
+}
+
+func TestCustomDealPatternSetsTheBatches(t *testing.T) {
+	fastGame(t)
+	t.Setenv("DEAL_PATTERN", "4,4,5")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	game.Manager.Mu.RLock()
+	trumpID := tb.room.Game.TrumpPlayer.ID
+	game.Manager.Mu.RUnlock()
+	if cards, _ := tb.client(t, trumpID).expect(t, "choose_trump")["cards"].([]interface{}); len(cards) != 4 {
+		t.Fatalf("choose_trump offered %d cards, want the first batch of 4", len(cards))
+	}
+	tb.chooseTrump(t, "spades")
+
+	for seat, c := range tb.clients {
+		if tb.ids[seat] != trumpID {
+			c.expect(t, "turn_update")
+		}
+		for i, want := range []int{4, 4, 5} {
+			typ := fmt.Sprintf("deal_cards_batch_%d", i+1)
+			batches := c.all(typ)
+			// The Trump Player's first batch is the one they chose trump from
+			if i == 0 && tb.ids[seat] == trumpID {
+				if len(batches) != 0 {
+					t.Fatalf("the Trump Player got %s again", typ)
+				}
+				continue
+			}
+			var batch struct {
+				Cards []game.Card `json:"cards"`
+			}
+			if len(batches) != 1 {
+				t.Fatalf("seat %d got %d of %s, want 1", seat, len(batches), typ)
+			}
+			decode(t, batches[0], &batch)
+			if len(batch.Cards) != want {
+				t.Fatalf("seat %d got %d cards in %s, want %d", seat, len(batch.Cards), typ, want)
+			}
+		}
+		if c.received("deal_cards_batch_4") {
+			t.Fatalf("seat %d got a fourth batch", seat)
+		}
+	}
+}
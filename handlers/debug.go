@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"hokm-backend/config"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// debugShowHands makes every player's game_update reveal all hands, like a coach's.
+// It's set once by EnableDebugHands and never changes afterwards.
+var debugShowHands bool
+
+// EnableDebugHands reads DEBUG_SHOW_HANDS at startup. With it on, a developer
+// running four clients on one machine sees every hand in each of them. It's a
+// development aid only and is refused in release mode (GIN_MODE=release).
+func EnableDebugHands() {
+	if !debugHandsRequested() {
+		return
+	}
+	debugShowHands = true
+	log.Println("🐞 DEBUG_SHOW_HANDS is on: every player sees every hand")
+}
+
+// debugHandsRequested reports whether DEBUG_SHOW_HANDS is on and allowed in this mode
+func debugHandsRequested() bool {
+	if config.GetEnv("DEBUG_SHOW_HANDS", "false") != "true" {
+		return false
+	}
+	if gin.Mode() == gin.ReleaseMode {
+		log.Println("⚠️ DEBUG_SHOW_HANDS is ignored in release mode")
+		return false
+	}
+	return true
+}
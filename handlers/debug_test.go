@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDebugHandsIsOnlyEnabledOutsideReleaseMode(t *testing.T) {
+	defer func(mode string) { gin.SetMode(mode) }(gin.Mode())
+
+	tests := []struct {
+		env  string
+		mode string
+		want bool
+	}{
+		{"", gin.DebugMode, false},
+		{"false", gin.DebugMode, false},
+		{"true", gin.ReleaseMode, false},
+		{"true", gin.DebugMode, true},
+	}
+	for _, tt := range tests {
+		t.Setenv("DEBUG_SHOW_HANDS", tt.env)
+		gin.SetMode(tt.mode)
+		if got := debugHandsRequested(); got != tt.want {
+			t.Errorf("DEBUG_SHOW_HANDS=%q in %s mode enabled = %v, want %v", tt.env, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestGameUpdatesStayMaskedWithTheDebugFlagOff(t *testing.T) {
+	fastGame(t)
+	t.Setenv("DEBUG_SHOW_HANDS", "false")
+	EnableDebugHands()
+	if debugShowHands {
+		t.Fatal("DEBUG_SHOW_HANDS=false turned the debug hands on")
+	}
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "clubs")
+	tb.play(t, tb.firstLegal)
+
+	for i, c := range tb.clients {
+		c.expect(t, "game_update")
+		for _, update := range c.all("game_update") {
+			if leaks := leakedHands(update, tb.ids[i]); len(leaks) > 0 {
+				t.Fatalf("game_update to %s shows the hands of %v", tb.ids[i], leaks)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+// holdSeatsFor shortens how long the room holds the seat of a disconnected player,
+// below the minimum RECONNECT_TIMEOUT allows
+func (tb *table) holdSeatsFor(d time.Duration) {
+	game.Manager.Mu.Lock()
+	tb.room.Settings.ReconnectTimeout = d
+	game.Manager.Mu.Unlock()
+}
+
+func TestReconnectTimeoutForfeitsUnderTheForfeitPolicy(t *testing.T) {
+	fastGame(t)
+	t.Setenv("DISCONNECT_POLICY", game.DisconnectForfeit)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	tb.holdSeatsFor(50 * time.Millisecond)
+	gone := tb.dropSeat(t, 2)
+
+	over := tb.clients[0].expect(t, "game_over")
+	if want := getOppositeTeam(gone.Team); over["winner"] != want {
+		t.Fatalf("game_over = %v, want %s to win by forfeit", over, want)
+	}
+	waitFor(t, "the player to be removed", func() bool { return len(tb.room.Players) == 3 })
+	game.Manager.Mu.RLock()
+	phase := tb.room.Game.Phase
+	game.Manager.Mu.RUnlock()
+	if phase != game.PhaseMatchOver {
+		t.Fatalf("phase %s after the forfeit, want %s", phase, game.PhaseMatchOver)
+	}
+}
+
+func TestReconnectTimeoutWaitsForAReplacementByDefault(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	tb.holdSeatsFor(50 * time.Millisecond)
+	gone := tb.dropSeat(t, 2)
+
+	// The roster without the player is the sign the timeout ran out
+	for {
+		var roster struct {
+			Players []struct {
+				ID string `json:"id"`
+			} `json:"players"`
+		}
+		decode(t, tb.clients[0].expect(t, MessageRosterUpdate), &roster)
+		if len(roster.Players) == 3 {
+			break
+		}
+	}
+	if tb.clients[0].received("game_over") {
+		t.Fatalf("game_over after %s timed out under %s", gone.ID, game.DisconnectWaitReplacement)
+	}
+	game.Manager.Mu.RLock()
+	phase := tb.room.Game.Phase
+	game.Manager.Mu.RUnlock()
+	if phase == game.PhaseMatchOver {
+		t.Fatalf("the match ended when %s timed out, want it to wait for a replacement", gone.ID)
+	}
+}
+
+func TestAFailedWriteDisconnectsThePlayer(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	game.Manager.Mu.RLock()
+	var broken *game.Player
+	for _, p := range tb.room.Players {
+		if p.ID == tb.ids[1] {
+			broken = p
+		}
+	}
+	// A payload that can't be encoded fails the write like a broken socket would
+	err := broken.Send(game.WSResponse{Type: "game_update", Payload: make(chan int)})
+	game.Manager.Mu.RUnlock()
+	if err == nil {
+		t.Fatal("Send() of a payload that can't be encoded succeeded")
+	}
+
+	waitFor(t, "the player to be marked disconnected", func() bool { return !broken.Connected })
+	if gone := tb.clients[0].expect(t, MessagePlayerDisconnected); gone["player_id"] != broken.ID {
+		t.Fatalf("player_disconnected = %v, want %s", gone, broken.ID)
+	}
+}
+
+func TestTheRoomsReconnectTimeoutDecidesWhenTheSeatIsGivenUp(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	const hold = 500 * time.Millisecond
+	tb.holdSeatsFor(hold)
+	dropped := time.Now()
+	gone := tb.dropSeat(t, 2)
+
+	seated := func() bool {
+		game.Manager.Mu.RLock()
+		defer game.Manager.Mu.RUnlock()
+		for _, p := range tb.room.Players {
+			if p.ID == gone.ID {
+				return true
+			}
+		}
+		return false
+	}
+	time.Sleep(hold / 2)
+	if !seated() {
+		t.Fatalf("%s lost the seat after %s of a %s timeout", gone.ID, time.Since(dropped), hold)
+	}
+	waitFor(t, "the player to be removed", func() bool { return len(tb.room.Players) == 3 })
+	if took := time.Since(dropped); took < hold {
+		t.Fatalf("%s was removed after %s, want at least %s", gone.ID, took, hold)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+)
+
+// stackDeck lays out a deck that deals hands[seat] to each seat of a 4-player table
+// whose Trump Player sits in seat 0: their first batch, then the first batch of the
+// other seats, then every later batch of the pattern seat by seat
+func stackDeck(hands [][]game.Card, pattern []int) []game.Card {
+	var deck []game.Card
+	dealt := make([]int, len(hands))
+	take := func(seat, n int) {
+		deck = append(deck, hands[seat][dealt[seat]:dealt[seat]+n]...)
+		dealt[seat] += n
+	}
+	take(0, pattern[0])
+	for i, n := range pattern {
+		for seat := range hands {
+			if i == 0 && seat == 0 {
+				continue
+			}
+			take(seat, n)
+		}
+	}
+	return deck
+}
+
+// wholeSuits gives each seat of a 4-player table a whole suit, spades to seat 0
+func wholeSuits(t *testing.T) [][]game.Card {
+	t.Helper()
+	suits := []string{"spades", "hearts", "diamonds", "clubs"}
+	hands := make([][]game.Card, len(suits))
+	for seat, suit := range suits {
+		for _, rank := range []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "A"} {
+			hands[seat] = append(hands[seat], card(t, suit, rank))
+		}
+	}
+	return hands
+}
+
+func TestFixedDeckPlaysAFullRoundThroughProcessMessage(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	// Seat 0 holds the spades, takes trump and every trick
+	hands := wholeSuits(t)
+	utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+		return stackDeck(hands, settings.DealBatches())
+	}
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+
+	// The Trump Player chooses from the top five cards of the deck
+	trumpPlayer := tb.client(t, tb.ids[0])
+	var prompt struct {
+		Cards []game.Card `json:"cards"`
+	}
+	decode(t, trumpPlayer.expect(t, "choose_trump"), &prompt)
+	if len(prompt.Cards) != 5 {
+		t.Fatalf("choose_trump offered %v", prompt.Cards)
+	}
+	for i, c := range game.SortHand(hands[0][:5], "") {
+		if prompt.Cards[i] != c {
+			t.Fatalf("choose_trump offered %v, want %v", prompt.Cards, hands[0][:5])
+		}
+	}
+	tb.chooseTrump(t, "spades")
+
+	game.Manager.Mu.RLock()
+	for _, p := range tb.room.Game.Players {
+		want := game.SortHand(hands[p.Index], "spades")
+		got := game.SortHand(p.Hand, "spades")
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("seat %d was dealt %v, want %v", p.Index, got, want)
+				break
+			}
+		}
+	}
+	var trumpTeam string
+	for _, p := range tb.room.Players {
+		if p.ID == tb.ids[0] {
+			trumpTeam = p.Team
+		}
+	}
+	game.Manager.Mu.RUnlock()
+
+	for tb.playing() {
+		tb.play(t, tb.firstLegal)
+	}
+
+	result := tb.clients[2].expect(t, "round_winner")
+	tricks, _ := result["tricks"].(map[string]interface{})
+	if result["winner"] != trumpTeam || result["trump_team"] != trumpTeam || tricks[trumpTeam] != float64(7) {
+		t.Fatalf("round_winner = %v, want %s to take all 7 tricks", result, trumpTeam)
+	}
+	completed := tb.clients[2].all("trick_complete")
+	if len(completed) != 7 {
+		t.Fatalf("%d tricks completed, want 7", len(completed))
+	}
+	for _, trick := range completed {
+		if trick["winner_id"] != tb.ids[0] {
+			t.Fatalf("trick_complete = %v, want every trick won by seat 0", trick)
+		}
+	}
+	tb.awaitSecondRound(t)
+}
+
+func TestDarkHokmDealsFullHandsAfterABlindChoice(t *testing.T) {
+	fastGame(t)
+	t.Setenv("GAME_MODE", game.ModeDark)
+	srv := newTestServer(t)
+
+	// Dark Hokm deals the whole deck seat by seat, so seat 0 gets all the spades
+	hands := wholeSuits(t)
+	utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+		var deck []game.Card
+		for _, hand := range hands {
+			deck = append(deck, hand...)
+		}
+		return deck
+	}
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+
+	trumpPlayer := tb.client(t, tb.ids[0])
+	prompt := trumpPlayer.expect(t, "choose_trump")
+	if cards, _ := prompt["cards"].([]interface{}); len(cards) != 0 || prompt["game_mode"] != game.ModeDark {
+		t.Fatalf("choose_trump = %v, want a blind prompt in dark mode", prompt)
+	}
+	if trumpPlayer.received("deal_cards_batch_1") {
+		t.Fatal("cards were dealt before the blind choice")
+	}
+	tb.chooseTrump(t, "hearts")
+
+	// The turn_update that starts play follows the last batch; chooseTrump already
+	// waited for the Trump Player's
+	for seat, c := range tb.clients {
+		if seat > 0 {
+			c.expect(t, "turn_update")
+		}
+		batches := c.all("deal_cards_batch_1")
+		if len(batches) != 1 {
+			t.Fatalf("seat %d got %d first batches, want 1", seat, len(batches))
+		}
+		var batch struct {
+			Cards []game.Card `json:"cards"`
+		}
+		decode(t, batches[0], &batch)
+		want := game.SortHand(hands[seat], "hearts")
+		if len(batch.Cards) != len(want) {
+			t.Fatalf("seat %d was dealt %v at once, want %v", seat, batch.Cards, want)
+		}
+		for i := range want {
+			if batch.Cards[i] != want[i] {
+				t.Fatalf("seat %d was dealt %v at once, want %v", seat, batch.Cards, want)
+			}
+		}
+		if c.received("deal_cards_batch_2") {
+			t.Fatalf("seat %d got a second batch in dark mode", seat)
+		}
+	}
+}
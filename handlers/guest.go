@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"hokm-backend/config"
+	"hokm-backend/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Guest issues a short-lived token for playing without an account. Nothing is stored:
+// the guest exists only in the token, and their games are left out of history stats.
+func Guest(c *gin.Context) {
+	guestID := "guest-" + uuid.NewString()
+	name := NameGenerator()
+
+	ttl := config.GetEnvDuration("GUEST_TOKEN_TTL", 2*time.Hour)
+	token, err := utils.GenerateGuestToken(guestID, name, ttl)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternal, "Failed to generate token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "name": name})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"hokm-backend/game"
+
+	"github.com/google/uuid"
+)
+
+func TestGuestsGetDistinctFriendlyNamesAndUUIDs(t *testing.T) {
+	fastGame(t)
+	t.Setenv("JWT_SECRET", "test-secret") // Guests need no database
+	// Seeded so the two random names differ
+	rng := game.Random
+	t.Cleanup(func() { game.Random = rng })
+	game.Random = game.NewRNG(1)
+	srv := newTestServer(t)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		rec := postJSON(t, Guest, "")
+		var resp struct {
+			Token string `json:"token"`
+			Name  string `json:"name"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); rec.Code != http.StatusOK || err != nil {
+			t.Fatalf("guest: status %d: %s", rec.Code, rec.Body)
+		}
+		if seen[resp.Name] || !friendly(resp.Name) {
+			t.Fatalf("guest %d is called %q after %v, want a new adjective and animal", i, resp.Name, seen)
+		}
+		seen[resp.Name] = true
+
+		join := dial(t, srv, "token="+resp.Token).expect(t, "join_room")
+		playerID, _ := join["your_id"].(string)
+		if _, err := uuid.Parse(playerID); err != nil || seen[playerID] {
+			t.Fatalf("guest %d got player ID %q, want a new UUID", i, playerID)
+		}
+		seen[playerID] = true
+
+		game.Manager.Mu.RLock()
+		for _, room := range game.Manager.Rooms {
+			for _, p := range room.Players {
+				if p.ID == playerID && p.Name != resp.Name {
+					t.Errorf("guest %d plays as %q, their token says %q", i, p.Name, resp.Name)
+				}
+			}
+		}
+		game.Manager.Mu.RUnlock()
+	}
+}
+
+// friendly reports whether name is one of the adjective and animal names
+func friendly(name string) bool {
+	for _, adjective := range nameAdjectives {
+		if animal := strings.TrimPrefix(name, adjective); animal != name {
+			for _, a := range nameAnimals {
+				if a == animal {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestGetHandReturnsTheHandAfterAPlay(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+
+	game.Manager.Mu.RLock()
+	leader := tb.room.Game.CurrentPlayer()
+	game.Manager.Mu.RUnlock()
+	tb.play(t, tb.firstLegal)
+
+	game.Manager.Mu.RLock()
+	want := game.SortHand(leader.Hand, tb.room.Game.TrumpSuit)
+	played := tb.room.Game.CurrentTrick[0]
+	game.Manager.Mu.RUnlock()
+
+	c := tb.client(t, leader.ID)
+	c.send(t, "get_hand", nil)
+	var got struct {
+		Hand  []game.Card `json:"hand"`
+		Count int         `json:"count"`
+	}
+	decode(t, c.expect(t, MessageHandSync), &got)
+
+	if got.Count != 12 || len(got.Hand) != 12 {
+		t.Fatalf("hand_sync has %d cards, count %d; want 12", len(got.Hand), got.Count)
+	}
+	for i, card := range got.Hand {
+		if card != want[i] {
+			t.Fatalf("hand_sync = %v, want %v", got.Hand, want)
+		}
+		if card == played {
+			t.Fatalf("hand_sync still holds the played %v", played)
+		}
+	}
+}
@@ -0,0 +1,489 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// waitTimeout bounds every wait of a test on the server
+const waitTimeout = 5 * time.Second
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	if os.Getenv("TEST_LOGS") == "" {
+		log.SetOutput(io.Discard)
+	}
+	os.Exit(m.Run())
+}
+
+// resetManager closes every room and empties the manager so every test starts
+// without rooms, and no timer of an earlier test fires during the next one
+func resetManager() {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+	for _, room := range game.Manager.Rooms {
+		closeRoom(room, MessageRoomDissolved, "The test is over.", CloseIdle)
+	}
+	game.Manager.Rooms = make(map[string]*game.Room)
+	game.Manager.Queue = nil
+}
+
+// fastGame removes every delay between the steps of a game and empties the manager
+func fastGame(t *testing.T) {
+	t.Helper()
+	t.Setenv("GAME_START_COUNTDOWN", "0s")
+	t.Setenv("TRICK_ADVANCE_DELAY", "0s")
+	t.Setenv("TRUMP_SELECTION", game.TrumpSelectionFixed)
+
+	dealDelay, batchInterval, roundDeck := utils.CardDealDelay, DealBatchInterval, utils.RoundDeck
+	utils.CardDealDelay = 0
+	DealBatchInterval = 0
+	t.Cleanup(func() {
+		utils.CardDealDelay, DealBatchInterval, utils.RoundDeck = dealDelay, batchInterval, roundDeck
+		resetManager()
+	})
+	resetManager()
+}
+
+// testDB points models.DB at a fresh in-memory sqlite database and sets a JWT secret
+func testDB(t *testing.T) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_DSN", "file:"+t.Name()+"?mode=memory&cache=shared")
+
+	db, err := models.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		models.DB = nil
+	})
+	if err := models.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newUser saves an account with the role and returns it with a token for it
+func newUser(t *testing.T, name, role string) (*models.User, string) {
+	t.Helper()
+	user := &models.User{Username: name, Password: "unused", Role: role}
+	if err := models.DB.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	token, err := utils.GenerateToken(fmt.Sprint(user.ID), user.Username, user.Role, user.TokenVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user, token
+}
+
+// newTestServer serves the WebSocket endpoint the way main.go does
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	router := gin.New()
+	router.GET("/ws", HandleWebSocket)
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testClient is a WebSocket client that keeps every message the server sent it
+type testClient struct {
+	ws   *websocket.Conn
+	mu   sync.Mutex
+	cond *sync.Cond
+	msgs []game.WSResponse
+	seen int // Messages already consumed by expect
+	done bool
+	err  error // Why the connection ended, once done
+}
+
+// dial connects a client to the server with the query string (e.g. "token=...")
+func dial(t *testing.T, srv *httptest.Server, query string) *testClient {
+	t.Helper()
+	c, err := connect(srv, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.ws.Close() })
+	return c
+}
+
+// dialAll connects n clients at the same time
+func dialAll(t *testing.T, srv *httptest.Server, n int) []*testClient {
+	t.Helper()
+	clients := make([]*testClient, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i], errs[i] = connect(srv, "")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := clients[i]
+		t.Cleanup(func() { c.ws.Close() })
+	}
+	return clients
+}
+
+// connect dials the server and starts reading what it sends
+func connect(srv *httptest.Server, query string) (*testClient, error) {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	if query != "" {
+		url += "?" + query
+	}
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", url, err)
+	}
+
+	c := &testClient{ws: ws}
+	c.cond = sync.NewCond(&c.mu)
+	go c.readLoop()
+	return c, nil
+}
+
+func (c *testClient) readLoop() {
+	for {
+		var resp game.WSResponse
+		err := c.ws.ReadJSON(&resp)
+
+		c.mu.Lock()
+		if err != nil {
+			c.done, c.err = true, err
+			c.cond.Broadcast()
+			c.mu.Unlock()
+			return
+		}
+		c.msgs = append(c.msgs, resp)
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	}
+}
+
+// send writes an action with its data to the server
+func (c *testClient) send(t *testing.T, action string, data interface{}) {
+	t.Helper()
+	c.sendMessage(t, map[string]interface{}{"action": action, "data": data})
+}
+
+// sendMessage writes a raw message to the server
+func (c *testClient) sendMessage(t *testing.T, msg interface{}) {
+	t.Helper()
+	if err := c.ws.WriteJSON(msg); err != nil {
+		t.Fatalf("send %v: %v", msg, err)
+	}
+}
+
+// closeCode waits for the server to close the connection and returns the close code
+func (c *testClient) closeCode(t *testing.T) int {
+	t.Helper()
+	timer := time.AfterFunc(waitTimeout, func() {
+		c.mu.Lock()
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	deadline := time.Now().Add(waitTimeout)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for !c.done {
+		if time.Now().After(deadline) {
+			t.Fatalf("connection still open after %v", waitTimeout)
+		}
+		c.cond.Wait()
+	}
+	var closeErr *websocket.CloseError
+	if !errors.As(c.err, &closeErr) {
+		t.Fatalf("connection ended with %v, want a close frame", c.err)
+	}
+	return closeErr.Code
+}
+
+// expect consumes messages until one of type typ arrives and returns its payload
+func (c *testClient) expect(t *testing.T, typ string) map[string]interface{} {
+	t.Helper()
+	resp, ok := c.next(func(r game.WSResponse) bool { return r.Type == typ })
+	if !ok {
+		t.Fatalf("no %s message within %v; got %v", typ, waitTimeout, c.types())
+	}
+	return payloadOf(resp)
+}
+
+// next consumes messages until one matches, reporting false on timeout or a closed connection
+func (c *testClient) next(match func(game.WSResponse) bool) (game.WSResponse, bool) {
+	deadline := time.Now().Add(waitTimeout)
+	timer := time.AfterFunc(waitTimeout, func() {
+		c.mu.Lock()
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		for c.seen < len(c.msgs) {
+			resp := c.msgs[c.seen]
+			c.seen++
+			if match(resp) {
+				return resp, true
+			}
+		}
+		if c.done || time.Now().After(deadline) {
+			return game.WSResponse{}, false
+		}
+		c.cond.Wait()
+	}
+}
+
+// received reports whether a message of type typ arrived so far, consumed or not
+func (c *testClient) received(typ string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range c.msgs {
+		if m.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// all returns the payloads of every message of type typ received so far
+func (c *testClient) all(typ string) []map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var payloads []map[string]interface{}
+	for _, m := range c.msgs {
+		if m.Type == typ {
+			payloads = append(payloads, payloadOf(m))
+		}
+	}
+	return payloads
+}
+
+// types lists the types of every message received so far, for failure output
+func (c *testClient) types() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	types := make([]string, len(c.msgs))
+	for i, m := range c.msgs {
+		types[i] = m.Type
+	}
+	return types
+}
+
+// payloadOf returns a message's payload as decoded JSON
+func payloadOf(resp game.WSResponse) map[string]interface{} {
+	payload, _ := resp.Payload.(map[string]interface{})
+	return payload
+}
+
+// decode converts a decoded JSON value into v
+func decode(t *testing.T, from interface{}, v interface{}) {
+	t.Helper()
+	raw, err := json.Marshal(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitFor polls cond under game.Manager.Mu.RLock until it holds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		game.Manager.Mu.RLock()
+		ok := cond()
+		game.Manager.Mu.RUnlock()
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// table is a full room of connected clients, indexed by seat
+type table struct {
+	room    *game.Room
+	clients []*testClient
+	ids     []string
+}
+
+// joinTable connects seats clients, which fill one room in seat order
+func joinTable(t *testing.T, srv *httptest.Server, seats int) *table {
+	t.Helper()
+	tb := &table{clients: make([]*testClient, seats), ids: make([]string, seats)}
+	for i := 0; i < seats; i++ {
+		c := dial(t, srv, "")
+		join := c.expect(t, "join_room")
+		tb.ids[i] = join["your_id"].(string)
+		tb.clients[i] = c
+	}
+
+	waitFor(t, "the room to fill", func() bool {
+		for _, room := range game.Manager.Rooms {
+			if len(room.Players) == seats {
+				tb.room = room
+				return true
+			}
+		}
+		return false
+	})
+	return tb
+}
+
+// joinAccountTable is joinTable for clients logged in to new accounts named prefix0,
+// prefix1, ..., so they can reconnect to their seat. It returns the token of each player ID.
+func joinAccountTable(t *testing.T, srv *httptest.Server, prefix string, seats int) (*table, map[string]string) {
+	t.Helper()
+	tokens := make(map[string]string)
+	byID := make(map[string]*testClient)
+	for i := 0; i < seats; i++ {
+		_, token := newUser(t, fmt.Sprint(prefix, i), models.RolePlayer)
+		c := dial(t, srv, "token="+token)
+		id := c.expect(t, "join_room")["your_id"].(string)
+		tokens[id], byID[id] = token, c
+	}
+	return tablesOf(t, byID, 1)[0], tokens
+}
+
+// client returns the client of the player with the ID
+func (tb *table) client(t *testing.T, playerID string) *testClient {
+	t.Helper()
+	for i, id := range tb.ids {
+		if id == playerID {
+			return tb.clients[i]
+		}
+	}
+	t.Fatalf("no client for player %s", playerID)
+	return nil
+}
+
+// startGame readies every seat and waits until the Trump Player is asked for trump
+func (tb *table) startGame(t *testing.T) {
+	t.Helper()
+	for _, c := range tb.clients {
+		c.send(t, "ready", nil)
+	}
+	waitFor(t, "the trump prompt", func() bool {
+		return tb.room.Game.Phase == game.PhaseWaitingTrump
+	})
+}
+
+// chooseTrump has the Trump Player pick the suit and waits until play can begin
+func (tb *table) chooseTrump(t *testing.T, suit string) {
+	t.Helper()
+	var trumpID string
+	waitFor(t, "a Trump Player", func() bool {
+		if tb.room.Game.TrumpPlayer == nil {
+			return false
+		}
+		trumpID = tb.room.Game.TrumpPlayer.ID
+		return true
+	})
+	c := tb.client(t, trumpID)
+	c.send(t, "choose_trump", suit)
+
+	// The first turn_update of the Round follows the last deal batch
+	c.expect(t, "turn_update")
+	waitFor(t, "play to begin", func() bool {
+		g := tb.room.Game
+		return g.Phase == game.PhasePlaying && g.CurrentPlayerID == trumpID
+	})
+}
+
+// awaitSecondRound waits until the second Round is dealt, so a test that ends the first
+// one doesn't return while the server still takes a deck from utils.RoundDeck
+func (tb *table) awaitSecondRound(t *testing.T) {
+	t.Helper()
+	waitFor(t, "the second Round to be dealt", func() bool {
+		g := tb.room.Game
+		return g.CurrentRound == 2 && (g.Phase == game.PhaseWaitingTrump || g.TrumpSuit != "")
+	})
+}
+
+// playedCards counts the cards played this match
+func playedCards(g *game.Game) int {
+	n := 0
+	for _, m := range g.Moves {
+		if m.Action == "play_card" {
+			n++
+		}
+	}
+	return n
+}
+
+// play has the player whose turn it is play a card picked by choose, and waits
+// until the server took it
+func (tb *table) play(t *testing.T, choose func(p *game.Player) game.Card) {
+	t.Helper()
+	var player *game.Player
+	var card game.Card
+	var before int
+	game.Manager.Mu.RLock()
+	player = tb.room.Game.CurrentPlayer()
+	if player != nil {
+		card = choose(player)
+	}
+	before = playedCards(tb.room.Game)
+	game.Manager.Mu.RUnlock()
+	if player == nil {
+		t.Fatal("nobody has the turn")
+	}
+
+	tb.client(t, player.ID).send(t, "play_card", map[string]interface{}{"suit": card.Suit, "rank": card.Rank})
+	waitFor(t, "the card to be played", func() bool {
+		return playedCards(tb.room.Game) > before
+	})
+}
+
+// firstLegal picks the first card the player may play
+func (tb *table) firstLegal(p *game.Player) game.Card {
+	return tb.room.Game.LegalCards(p.Hand)[0]
+}
+
+// card builds a card of the standard Ace-high order
+func card(t *testing.T, suit, rank string) game.Card {
+	t.Helper()
+	c, err := game.NewCard(suit, rank)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
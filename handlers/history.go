@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// saveGameHistory stores the result of a finished match. The record is copied out of
+// the room right away, as the caller holds game.Manager.Mu, and written to the
+// database from a separate goroutine so the lock isn't held for the insert.
+func saveGameHistory(room *game.Room, winner string) {
+	if models.DB == nil {
+		return
+	}
+
+	players := make([]string, 0, len(room.Game.Players))
+	var seats []game.GameSeat
+	for i, p := range room.Game.Players {
+		players = append(players, p.Name)
+		if p.UserID == "" || p.IsGuest {
+			continue
+		}
+		seat := i
+		seats = append(seats, game.GameSeat{
+			UserID: p.UserID,
+			Seat:   &seat,
+			Team:   p.Team,
+			Won:    p.Team == winner,
+			Kots:   room.Game.Kots[p.Team],
+		})
+	}
+
+	playerTricks := make(map[string]int, len(room.Game.TotalTricksWon))
+	for id, n := range room.Game.TotalTricksWon {
+		playerTricks[id] = n
+	}
+
+	history := game.GameHistory{
+		Players:      players,
+		Winner:       winner,
+		Score:        room.Game.RoundScores[winner],
+		PlayerTricks: playerTricks,
+		Moves:        append([]game.MoveRecord(nil), room.Game.Moves...),
+		Scoring:      room.Settings.Scoring,
+		Seats:        seats,
+	}
+	roomID := room.ID
+	goSafe("save game history of room "+roomID, func() {
+		if err := models.DB.Create(&history).Error; err != nil {
+			log.Printf("Failed to save game history for room %s: %v", roomID, err)
+		}
+	})
+}
+
+// GetGameMoves returns the replay log of a finished game
+func GetGameMoves(c *gin.Context) {
+	db, cancel := requestDB(c)
+	defer cancel()
+
+	var history game.GameHistory
+	if err := db.First(&history, c.Param("id")).Error; err != nil {
+		respondDBError(c, err, http.StatusNotFound, utils.CodeNotFound, "Game not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"game_id": history.ID, "moves": history.Moves})
+}
+
+// GetGameReplay rebuilds a finished game from its replay log, returning the state
+// after every move so a client can step through the match
+func GetGameReplay(c *gin.Context) {
+	db, cancel := requestDB(c)
+	defer cancel()
+
+	var history game.GameHistory
+	if err := db.First(&history, c.Param("id")).Error; err != nil {
+		respondDBError(c, err, http.StatusNotFound, utils.CodeNotFound, "Game not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"game_id": history.ID,
+		"winner":  history.Winner,
+		"score":   history.Score,
+		"states":  game.ReplayGame(history.Moves, history.Scoring),
+	})
+}
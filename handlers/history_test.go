@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hokm-backend/game"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// savedHistory waits for the finished match to be saved and returns it
+func savedHistory(t *testing.T) game.GameHistory {
+	t.Helper()
+	var history game.GameHistory
+	waitFor(t, "the game history", func() bool {
+		return models.DB.First(&history).Error == nil
+	})
+	return history
+}
+
+// fetchReplay asks GET /history/:id/replay for the states of the saved match
+func fetchReplay(t *testing.T, id uint) []game.GameState {
+	t.Helper()
+	router := gin.New()
+	router.GET("/history/:id/replay", GetGameReplay)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fmt.Sprint("/history/", id, "/replay"), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("replay: status %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		States []game.GameState `json:"states"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.States) == 0 {
+		t.Fatal("the replay has no states")
+	}
+	return resp.States
+}
+
+func TestReplayScoresByTheRoomsRules(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	t.Setenv("SCORE_KOT", "7") // One Kot takes the match
+	srv := newTestServer(t)
+
+	hands := wholeSuits(t)
+	utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+		return stackDeck(hands, settings.DealBatches())
+	}
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+	for tb.playing() {
+		tb.play(t, tb.firstLegal)
+	}
+	tb.clients[0].expect(t, "game_over")
+
+	history := savedHistory(t)
+	if history.Scoring.Kot != 7 || history.Score != 7 {
+		t.Fatalf("saved scoring %+v with score %d, want a Kot worth 7", history.Scoring, history.Score)
+	}
+	final := fetchReplay(t, history.ID)
+	last := final[len(final)-1]
+	if last.RoundScores[history.Winner] != history.Score {
+		t.Fatalf("replay ends %v, stored result is %d for %s", last.RoundScores, history.Score, history.Winner)
+	}
+}
+
+func TestReplayOfAForfeitedMatch(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	t.Setenv("DISCONNECT_POLICY", game.DisconnectForfeit)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	for i := 0; i < 5; i++ {
+		tb.play(t, tb.firstLegal)
+	}
+	kicked := tb.dropSeat(t, 2)
+	tb.clients[1].send(t, "vote_kick", kicked.ID)
+	tb.clients[3].send(t, "vote_kick", kicked.ID)
+	tb.clients[0].expect(t, "game_over")
+
+	history := savedHistory(t)
+	states := fetchReplay(t, history.ID)
+	last := states[len(states)-1]
+	if last.Move.Action != "forfeit" || last.Move.Team != kicked.Team {
+		t.Fatalf("replay ends with %+v, want the forfeit of %s", last.Move, kicked.Team)
+	}
+	if last.RoundScores[history.Winner] != history.Score || last.RoundScores[kicked.Team] != 0 {
+		t.Fatalf("replay ends %v, stored result is %d for %s", last.RoundScores, history.Score, history.Winner)
+	}
+}
+
+func TestATrickRecordsFourMovesInPlayOrder(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	trick := tb.playFirstTrick(t)
+
+	game.Manager.Mu.RLock()
+	moves := tb.room.Game.Moves
+	game.Manager.Mu.RUnlock()
+	var plays []game.MoveRecord
+	for _, m := range moves {
+		if m.Action == "play_card" {
+			plays = append(plays, m)
+		}
+	}
+	if len(plays) != 4 {
+		t.Fatalf("%d play_card moves after a trick, want 4", len(plays))
+	}
+	for i, m := range plays {
+		if m.PlayerID != trick.PlayerIDs[i] || m.Card == nil || *m.Card != trick.Cards[i] {
+			t.Errorf("move %d = %s played %v, want %s playing %v", i, m.PlayerID, m.Card, trick.PlayerIDs[i], trick.Cards[i])
+		}
+		if i > 0 && m.Timestamp.Before(plays[i-1].Timestamp) {
+			t.Errorf("move %d is stamped before the move it followed", i)
+		}
+	}
+}
+
+func TestAGuestsMatchIsSavedWithoutTheirSeat(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	t.Setenv("DISCONNECT_POLICY", game.DisconnectForfeit)
+	srv := newTestServer(t)
+
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(postJSON(t, Guest, "").Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	// The guest joins first and takes seat 0
+	byID := make(map[string]*testClient)
+	guest := dial(t, srv, "token="+resp.Token)
+	guestID := guest.expect(t, "join_room")["your_id"].(string)
+	byID[guestID] = guest
+	for i := 0; i < 3; i++ {
+		_, token := newUser(t, fmt.Sprint("member", i), models.RolePlayer)
+		c := dial(t, srv, "token="+token)
+		byID[c.expect(t, "join_room")["your_id"].(string)] = c
+	}
+	tb := tablesOf(t, byID, 1)[0]
+
+	game.Manager.Mu.RLock()
+	seated := *tb.room.Players[0]
+	game.Manager.Mu.RUnlock()
+	if seated.ID != guestID || !seated.IsGuest || seated.UserID == "" {
+		t.Fatalf("seat 0 is %s with IsGuest %v, want the guest %s", seated.ID, seated.IsGuest, guestID)
+	}
+
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	kicked := tb.dropSeat(t, 2)
+	tb.clients[1].send(t, "vote_kick", kicked.ID)
+	tb.clients[3].send(t, "vote_kick", kicked.ID)
+	tb.clients[0].expect(t, "game_over")
+
+	history := savedHistory(t)
+	var seats []game.GameSeat
+	if err := models.DB.Where("game_history_id = ?", history.ID).Find(&seats).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(seats) != 3 {
+		t.Fatalf("%d seats saved, want the 3 accounts", len(seats))
+	}
+	var guestRows int64
+	models.DB.Model(&game.GameSeat{}).Where("user_id = ?", seated.UserID).Count(&guestRows)
+	if guestRows != 0 {
+		t.Fatalf("%d history rows saved for the guest", guestRows)
+	}
+}
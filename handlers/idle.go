@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"sync"
+	"time"
+)
+
+// idleClock is the clock idle players are timed with. Tests replace it to skip ahead.
+var idleClock = time.Now
+
+// IdleCheckInterval is how often a connection is checked for having gone idle
+var IdleCheckInterval = time.Second
+
+// idleWatch times how long a connection has sent nothing. Once that passes the
+// timeout it expires the connection's read deadline, so the read loop fails with
+// a timeout and gives up the seat.
+type idleWatch struct {
+	mu    sync.Mutex
+	clock func() time.Time
+	last  time.Time
+	done  chan struct{}
+}
+
+// watchIdle starts timing conn, or returns nil when timeout is 0
+func watchIdle(conn *game.Conn, timeout time.Duration) *idleWatch {
+	if timeout <= 0 {
+		return nil
+	}
+	w := &idleWatch{clock: idleClock, done: make(chan struct{})}
+	w.last = w.clock()
+	interval := IdleCheckInterval
+
+	goSafe("idle watch of "+conn.RemoteAddr().String(), func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+
+			w.mu.Lock()
+			idle := w.clock().Sub(w.last)
+			w.mu.Unlock()
+			if idle > timeout {
+				conn.SetReadDeadline(time.Now())
+				return
+			}
+		}
+	})
+	return w
+}
+
+// touch records a message from the client
+func (w *idleWatch) touch() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	w.last = w.clock()
+	w.mu.Unlock()
+}
+
+// stop ends the watch once the read loop is over
+func (w *idleWatch) stop() {
+	if w != nil {
+		close(w.done)
+	}
+}
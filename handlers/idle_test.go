@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+// fakeClock is a clock that only moves when the test advances it
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// useFakeClock times idle players with a fake clock checked every few milliseconds
+func useFakeClock(t *testing.T) *fakeClock {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	oldClock, oldInterval := idleClock, IdleCheckInterval
+	idleClock, IdleCheckInterval = clock.Now, 5*time.Millisecond
+	t.Cleanup(func() { idleClock, IdleCheckInterval = oldClock, oldInterval })
+	return clock
+}
+
+func TestIdlePlayersAreDisconnected(t *testing.T) {
+	fastGame(t)
+	t.Setenv("PLAYER_IDLE_TIMEOUT", "10m")
+	clock := useFakeClock(t)
+	srv := newTestServer(t)
+
+	active, idle := dial(t, srv, ""), dial(t, srv, "")
+	active.expect(t, "join_room")
+	idleID := idle.expect(t, "join_room")["your_id"].(string)
+
+	clock.advance(9 * time.Minute)
+	active.send(t, "get_hand", nil)
+	active.expect(t, MessageHandSync)
+
+	clock.advance(2 * time.Minute)
+	if code := idle.closeCode(t); code != CloseIdle {
+		t.Fatalf("idle player closed with %d, want %d", code, CloseIdle)
+	}
+	waitFor(t, "the idle player's seat to be given up", func() bool {
+		for _, room := range game.Manager.Rooms {
+			for _, p := range room.Players {
+				if p.ID == idleID {
+					return false
+				}
+			}
+		}
+		return true
+	})
+
+	// 2 minutes since its last message, the active player is still connected
+	active.send(t, "get_hand", nil)
+	active.expect(t, MessageHandSync)
+}
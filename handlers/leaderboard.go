@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"fmt"
+	"hokm-backend/config"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultLeaderboardLimit = 10
+	MaxLeaderboardLimit     = 100
+)
+
+// leaderboardCache keeps each metric and limit's leaderboard for LEADERBOARD_CACHE_TTL
+// (30 seconds by default), so a busy page doesn't aggregate game_seats on every request
+var leaderboardCache = struct {
+	mu      sync.Mutex
+	entries map[string]cachedLeaderboard
+}{entries: make(map[string]cachedLeaderboard)}
+
+type cachedLeaderboard struct {
+	board     []models.LeaderboardEntry
+	expiresAt time.Time
+}
+
+// Leaderboard returns the top users by ?metric=wins (the default) or ?metric=winrate,
+// the latter only counting users with LEADERBOARD_MIN_GAMES finished matches
+func Leaderboard(c *gin.Context) {
+	metric := c.DefaultQuery("metric", models.MetricWins)
+	if metric != models.MetricWins && metric != models.MetricWinRate {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidRequest, "metric must be wins or winrate")
+		return
+	}
+
+	limit := DefaultLeaderboardLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxLeaderboardLimit {
+			utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidRequest,
+				fmt.Sprintf("limit must be between 1 and %d", MaxLeaderboardLimit))
+			return
+		}
+		limit = n
+	}
+
+	key := fmt.Sprintf("%s:%d", metric, limit)
+	now := time.Now()
+	leaderboardCache.mu.Lock()
+	cached, ok := leaderboardCache.entries[key]
+	leaderboardCache.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		c.JSON(http.StatusOK, gin.H{"metric": metric, "leaderboard": cached.board})
+		return
+	}
+
+	db, cancel := requestDB(c)
+	defer cancel()
+
+	minGames := config.GetEnvInt("LEADERBOARD_MIN_GAMES", 10)
+	board, err := models.Leaderboard(db, metric, limit, minGames)
+	if err != nil {
+		respondDBError(c, err, http.StatusInternalServerError, utils.CodeInternal, "Failed to load the leaderboard")
+		return
+	}
+	if board == nil {
+		board = []models.LeaderboardEntry{}
+	}
+
+	ttl := config.GetEnvDuration("LEADERBOARD_CACHE_TTL", 30*time.Second)
+	leaderboardCache.mu.Lock()
+	leaderboardCache.entries[key] = cachedLeaderboard{board: board, expiresAt: now.Add(ttl)}
+	leaderboardCache.mu.Unlock()
+
+	c.JSON(http.StatusOK, gin.H{"metric": metric, "leaderboard": board})
+}
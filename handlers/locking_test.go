@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+
+	"hokm-backend/game"
+)
+
+// Run with -race: tables that join, play and lose a player at the same time must not
+// touch shared state outside game.Manager.Mu
+func TestConcurrentTablesJoinPlayAndDisconnect(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+	const tables = 2
+
+	clients := dialAll(t, srv, 4*tables)
+	byID := make(map[string]*testClient, len(clients))
+	for _, c := range clients {
+		byID[c.expect(t, "join_room")["your_id"].(string)] = c
+	}
+	tbs := tablesOf(t, byID, tables)
+
+	leavers := make([]string, tables)
+	t.Run("play", func(t *testing.T) {
+		for i, tb := range tbs {
+			i, tb := i, tb
+			t.Run(tb.room.ID, func(t *testing.T) {
+				t.Parallel()
+				tb.startGame(t)
+				tb.chooseTrump(t, "hearts")
+				for n := 0; n < 6; n++ {
+					tb.play(t, tb.firstLegal)
+				}
+
+				// Someone who isn't on turn leaves and drops the connection
+				game.Manager.Mu.RLock()
+				for _, p := range tb.room.Players {
+					if p.ID != tb.room.Game.CurrentPlayerID {
+						leavers[i] = p.ID
+						break
+					}
+				}
+				game.Manager.Mu.RUnlock()
+				leaver := tb.client(t, leavers[i])
+				leaver.send(t, "leave_game", nil)
+				waitFor(t, "the game to pause", tb.room.Game.Halted)
+				leaver.ws.Close()
+			})
+		}
+	})
+
+	// Replacements take whichever saved seat they find
+	for _, c := range dialAll(t, srv, tables) {
+		id := c.expect(t, MessagePlayerReplaced)["new_player_id"].(string)
+		for _, tb := range tbs {
+			for seat, seatID := range tb.ids {
+				if seatID == id {
+					tb.clients[seat] = c
+				}
+			}
+		}
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		for _, tb := range tbs {
+			tb := tb
+			t.Run(tb.room.ID, func(t *testing.T) {
+				t.Parallel()
+				waitFor(t, "the game to resume", func() bool { return !tb.room.Game.Halted() })
+				for tb.playing() {
+					tb.play(t, tb.firstLegal)
+				}
+				tb.clients[0].expect(t, "round_winner")
+				waitFor(t, "the next Round", func() bool {
+					return tb.room.Game.Phase == game.PhaseWaitingTrump && tb.room.Game.CurrentRound == 2
+				})
+			})
+		}
+	})
+}
+
+// playing reports whether the table is still in the playing phase of its Round
+func (tb *table) playing() bool {
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	return tb.room.Game.Phase == game.PhasePlaying
+}
+
+// tablesOf groups the connected clients by the room they were seated in
+func tablesOf(t *testing.T, byID map[string]*testClient, want int) []*table {
+	t.Helper()
+	var tbs []*table
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	for _, room := range game.Manager.Rooms {
+		tb := &table{room: room}
+		for _, p := range room.Players {
+			tb.ids = append(tb.ids, p.ID)
+			tb.clients = append(tb.clients, byID[p.ID])
+		}
+		tbs = append(tbs, tb)
+	}
+	if len(tbs) != want {
+		t.Fatalf("clients were seated in %d rooms, want %d", len(tbs), want)
+	}
+	return tbs
+}
+
+func TestConcurrentStartsDealOnlyOnce(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	clients := dialAll(t, srv, 4)
+	byID := make(map[string]*testClient, len(clients))
+	for _, c := range clients {
+		byID[c.expect(t, "join_room")["your_id"].(string)] = c
+	}
+	tb := tablesOf(t, byID, 1)[0]
+
+	// Every caller that sees the table full tries to deal it
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			initializeGame(tb.room)
+		}()
+	}
+	wg.Wait()
+
+	game.Manager.Mu.RLock()
+	phase, trumpPlayer := tb.room.Game.Phase, tb.room.Game.TrumpPlayer
+	err := tb.room.Game.ValidateDeal(firstBatchCounts(tb.room), tb.room.Settings.DeckSize())
+	game.Manager.Mu.RUnlock()
+	if phase != game.PhaseWaitingTrump || err != nil {
+		t.Fatalf("phase %s after the concurrent starts, deal check %v; want one clean deal", phase, err)
+	}
+	tb.client(t, trumpPlayer.ID).expect(t, "choose_trump")
+	for seat, c := range tb.clients {
+		if prompts := c.all("choose_trump"); len(prompts) > 1 || (len(prompts) == 1) != (tb.ids[seat] == trumpPlayer.ID) {
+			t.Fatalf("seat %d got %d trump prompts, want one for the Trump Player only", seat, len(prompts))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import "hokm-backend/game"
+
+// maskGameStateFor builds the "game" payload of a game_update as seen by viewerID.
+// The viewer only ever sees their own hand; a spectator (an empty or unseated
+// viewerID) sees no hands at all. Every other player's hand is reduced to nil.
+func maskGameStateFor(viewerID string, room *game.Room) map[string]interface{} {
+	trumpPlayerID := ""
+	if room.Game.TrumpPlayer != nil {
+		trumpPlayerID = room.Game.TrumpPlayer.ID
+	}
+	dealerID := ""
+	if dealer := room.Game.Dealer(); dealer != nil {
+		dealerID = dealer.ID
+	}
+
+	return withScores(map[string]interface{}{
+		"players":            maskPlayersFor(viewerID, room, room.Game.Players),
+		"trump_player_id":    trumpPlayerID,
+		"dealer_id":          dealerID,
+		"trump_suit":         room.Game.TrumpSuit,
+		"current_trick":      room.Game.CurrentTrick,
+		"current_player_idx": room.Game.CurrentPlayerIndex(),
+		"current_player_id":  room.Game.CurrentPlayerID,
+		"game_mode":          room.Settings.GameMode,
+		"phase":              room.Game.Phase,
+		"hand_counts":        handCounts(room),
+	}, room)
+}
+
+// coachGameState builds the "game" payload for a coach: the same as
+// maskGameStateFor but with every hand revealed. Never send it to a seated
+// player or a regular spectator.
+func coachGameState(room *game.Room) map[string]interface{} {
+	state := maskGameStateFor("", room)
+
+	players := make([]*game.Player, len(room.Game.Players))
+	for i, p := range room.Game.Players {
+		players[i] = p.Clone()
+		players[i].Hand = game.SortHand(p.Hand, room.Game.TrumpSuit)
+	}
+	state["players"] = players
+	return state
+}
+
+// maskPlayersFor copies the players with every hand but the viewer's own removed
+func maskPlayersFor(viewerID string, room *game.Room, players []*game.Player) []*game.Player {
+	masked := make([]*game.Player, len(players))
+	for i, p := range players {
+		playerCopy := p.Clone()
+		playerCopy.Hand = nil // Will be omitted in JSON
+		if viewerID != "" && p.ID == viewerID {
+			playerCopy.Hand = visibleHand(p, room)
+		}
+		masked[i] = playerCopy
+	}
+	return masked
+}
+
+// visibleHand returns the player's own hand sorted for display. In dark Hokm
+// nobody sees their cards before trump is chosen.
+func visibleHand(player *game.Player, room *game.Room) []game.Card {
+	if room.Settings.GameMode == game.ModeDark && isWaitingForTrump(room) {
+		return []game.Card{}
+	}
+	return game.SortHand(player.Hand, room.Game.TrumpSuit)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+// leakedHands walks a decoded payload and returns the IDs of the players whose hand it
+// shows, other than self's. A spectator passes an empty self.
+func leakedHands(v interface{}, self string) []string {
+	var leaks []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		// A hand belongs to the player it's listed with; a bare hand is the recipient's own
+		hand, _ := v["hand"].([]interface{})
+		if id, ok := v["id"].(string); ok && len(hand) > 0 && (self == "" || id != self) {
+			leaks = append(leaks, id)
+		}
+		if dealt, ok := v["cards"].(map[string]interface{}); ok {
+			for id := range dealt {
+				if self == "" || id != self {
+					leaks = append(leaks, id)
+				}
+			}
+		}
+		for _, child := range v {
+			leaks = append(leaks, leakedHands(child, self)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			leaks = append(leaks, leakedHands(child, self)...)
+		}
+	}
+	return leaks
+}
+
+func TestNoPayloadShowsAnotherPlayersHand(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	spectator := dial(t, srv, "watch="+tb.room.ID)
+	spectator.expect(t, MessageObserverJoined)
+	tb.startGame(t)
+	tb.chooseTrump(t, "diamonds")
+	for i := 0; i < 6; i++ {
+		tb.play(t, tb.firstLegal)
+	}
+
+	// The hand_sync each player asks for is the last thing they are sent
+	for _, c := range tb.clients {
+		c.send(t, "get_hand", nil)
+		c.expect(t, MessageHandSync)
+	}
+	spectator.expect(t, "game_update")
+
+	clients := append([]*testClient{spectator}, tb.clients...)
+	selves := append([]string{""}, tb.ids...)
+	for i, c := range clients {
+		c.mu.Lock()
+		msgs := append([]game.WSResponse(nil), c.msgs...)
+		c.mu.Unlock()
+		for _, m := range msgs {
+			var payload interface{}
+			decode(t, m.Payload, &payload)
+			if leaks := leakedHands(payload, selves[i]); len(leaks) > 0 {
+				t.Errorf("%s sent to %q shows the hands of %v", m.Type, selves[i], leaks)
+			}
+		}
+	}
+}
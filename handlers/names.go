@@ -0,0 +1,23 @@
+package handlers
+
+import "hokm-backend/game"
+
+// NameGenerator picks the display name of guests and anonymous players. Replace it
+// to localize the names or use another scheme.
+var NameGenerator = friendlyName
+
+var (
+	nameAdjectives = []string{
+		"Brave", "Clever", "Swift", "Lucky", "Quiet", "Bold", "Jolly", "Sly",
+		"Gentle", "Mighty", "Nimble", "Witty", "Calm", "Eager", "Proud", "Sunny",
+	}
+	nameAnimals = []string{
+		"Otter", "Falcon", "Tiger", "Panda", "Fox", "Lynx", "Heron", "Wolf",
+		"Gazelle", "Badger", "Camel", "Owl", "Leopard", "Dolphin", "Hawk", "Raven",
+	}
+)
+
+// friendlyName returns an adjective and an animal, e.g. "SwiftOtter"
+func friendlyName() string {
+	return nameAdjectives[game.Random.Intn(len(nameAdjectives))] + nameAnimals[game.Random.Intn(len(nameAnimals))]
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+)
+
+// playKotRound deals seat 0 every spade and plays the Round out with spades as trump
+func playKotRound(t *testing.T, tb *table) {
+	t.Helper()
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+	for tb.playing() {
+		tb.play(t, tb.firstLegal)
+	}
+}
+
+func TestNextRoundWaitsForTrumpBeforeTheTurn(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	hands := wholeSuits(t)
+	utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+		return stackDeck(hands, settings.DealBatches())
+	}
+
+	tb := joinTable(t, srv, 4)
+	playKotRound(t, tb)
+
+	// Seat 0 kept the trump, so they're asked again
+	c := tb.client(t, tb.ids[0])
+	c.expect(t, "round_winner")
+	c.mu.Lock()
+	from := len(c.msgs)
+	c.mu.Unlock()
+	c.expect(t, "choose_trump")
+	c.send(t, "get_hand", nil)
+	c.expect(t, MessageHandSync)
+
+	for _, typ := range c.types()[from:] {
+		if typ == "turn_update" {
+			t.Fatalf("turn_update before trump was chosen: %v", c.types()[from:])
+		}
+	}
+	game.Manager.Mu.RLock()
+	phase, current := tb.room.Game.Phase, tb.room.Game.CurrentPlayerID
+	game.Manager.Mu.RUnlock()
+	if phase != game.PhaseWaitingTrump || current != "" {
+		t.Fatalf("phase %s with %q on turn, want %s with nobody on turn", phase, current, game.PhaseWaitingTrump)
+	}
+
+	// The turn goes out once the new hands are dealt
+	c.send(t, "choose_trump", "hearts")
+	if got := c.expect(t, "turn_update")["current_player"]; got != tb.ids[0] {
+		t.Fatalf("turn_update for %v, want the Trump Player %s", got, tb.ids[0])
+	}
+}
+
+func TestNextRoundDealFailurePausesTheRoom(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	hands := wholeSuits(t)
+	rounds := 0
+	utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+		rounds++
+		if rounds > 1 {
+			return nil
+		}
+		return stackDeck(hands, settings.DealBatches())
+	}
+
+	tb := joinTable(t, srv, 4)
+	playKotRound(t, tb)
+
+	tb.clients[1].expect(t, "game_paused")
+	waitFor(t, "the room to pause", func() bool { return tb.room.Game.Phase == game.PhasePaused })
+}
+
+func TestRoundEndWithoutATrumpPlayerPauses(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	for i := 0; i < 3; i++ {
+		tb.play(t, tb.firstLegal)
+	}
+
+	// Whoever takes the trick takes the Round, and the Trump Player is gone
+	game.Manager.Mu.Lock()
+	tb.room.Game.Scores = map[string]int{"team1": 6, "team2": 6}
+	tb.room.Game.TrumpPlayer = nil
+	last := tb.room.Game.CurrentPlayer()
+	game.Manager.Mu.Unlock()
+	tb.play(t, tb.firstLegal)
+
+	paused := tb.clients[0].expect(t, "game_paused")
+	if paused["message"] != "Trump Player left. Game paused." {
+		t.Fatalf("game_paused = %v, want the missing Trump Player as the reason", paused)
+	}
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	if !tb.room.Game.Halted() || !last.Connected {
+		t.Fatalf("halted=%t, last player connected=%t; want the Round paused and nobody dropped", tb.room.Game.Halted(), last.Connected)
+	}
+}
+
+func TestDealerRotatesEveryRoundWhileTheTrumpPlayerFollowsTheResult(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	// Seat 0 is dealt every spade and seat 1 every heart
+	hands := wholeSuits(t)
+	utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+		return stackDeck(hands, settings.DealBatches())
+	}
+
+	tb := joinTable(t, srv, 4)
+	playKotRound(t, tb)
+
+	// With hearts as trump seat 1 takes every trick, so the Trump Player's team loses
+	tb.awaitSecondRound(t)
+	tb.chooseTrump(t, "hearts")
+	for tb.playing() {
+		tb.play(t, tb.firstLegal)
+	}
+	waitFor(t, "the third Round", func() bool {
+		return tb.room.Game.CurrentRound == 3 && tb.room.Game.Phase == game.PhaseWaitingTrump
+	})
+
+	watcher := tb.clients[2]
+	var starts []map[string]interface{}
+	for len(starts) < 3 {
+		starts = append(starts, watcher.expect(t, MessageRoundStart))
+	}
+	first := -1
+	for seat, id := range tb.ids {
+		if id == starts[0]["dealer_id"] {
+			first = seat
+		}
+	}
+	if first == -1 {
+		t.Fatalf("round_start = %v, want a seated dealer", starts[0])
+	}
+	// The Trump Player kept the trump after winning Round 1 and passed it on after losing Round 2
+	trumpPlayers := []string{tb.ids[0], tb.ids[0], tb.ids[1]}
+	for i, start := range starts {
+		if start["round"] != float64(i+1) {
+			t.Fatalf("round_start %d = %v, want round %d", i, start, i+1)
+		}
+		if want := tb.ids[(first+i)%4]; start["dealer_id"] != want {
+			t.Errorf("round %d is dealt by %v, want %s one seat on", i+1, start["dealer_id"], want)
+		}
+		if start["trump_player_id"] != trumpPlayers[i] {
+			t.Errorf("round %d has Trump Player %v, want %s", i+1, start["trump_player_id"], trumpPlayers[i])
+		}
+	}
+}
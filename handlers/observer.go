@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+const MessageObserverJoined = "observer_joined"
+
+// watchRoom lets a connection watch a room without a seat until it disconnects.
+// Accounts with the coach or admin role see every hand, anyone else sees none.
+// Finished rooms can't be watched, nor rooms the account is seated in; with running
+// set, neither can rooms still in the lobby.
+func watchRoom(conn *game.Conn, roomID string, id identity, running bool) {
+	coach := seesHands(id)
+
+	game.Manager.Mu.Lock()
+	room, ok := game.Manager.Rooms[roomID]
+	if !ok {
+		game.Manager.Mu.Unlock()
+		sendError(conn, utils.CodeNotFound, "Room not found")
+		return
+	}
+	if seated, _ := game.Manager.FindRoomByUserIDLocked(id.UserID); seated == room {
+		game.Manager.Mu.Unlock()
+		sendError(conn, utils.CodeAlreadyInGame, "You can't watch a room you're seated in")
+		return
+	}
+	if over, _ := room.Game.IsMatchOver(); over {
+		game.Manager.Mu.Unlock()
+		sendError(conn, utils.CodeWrongPhase, "The game in this room is over")
+		return
+	}
+	if running && !room.Started {
+		game.Manager.Mu.Unlock()
+		sendError(conn, utils.CodeWrongPhase, "The game in this room hasn't started")
+		return
+	}
+
+	observer := &game.Observer{
+		ID:     "observer-" + uuid.NewString(),
+		UserID: id.UserID,
+		Coach:  coach,
+		Conn:   conn,
+	}
+	conn.SetEvents(room.Events, observer.ID)
+	if room.Observers == nil {
+		room.Observers = make(map[string]*game.Observer)
+	}
+	room.Observers[observer.ID] = observer
+	game.Manager.Mu.Unlock()
+
+	log.Printf("👀 %s is watching room %s (coach: %t)", observer.ID, room.ID, coach)
+	conn.WriteJSON(game.WSResponse{
+		Type: MessageObserverJoined,
+		Payload: map[string]interface{}{
+			"room_id": room.ID,
+			"your_id": observer.ID,
+			"coach":   coach,
+		},
+	})
+	if room.Started {
+		game.Manager.Mu.RLock()
+		sendObserverUpdate(observer, room)
+		game.Manager.Mu.RUnlock()
+	}
+
+	// Observers can't act; keep reading only to notice the disconnect
+	defer recoverPanic("observer " + observer.ID)
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	game.Manager.Mu.Lock()
+	delete(room.Observers, observer.ID)
+	game.Manager.Mu.Unlock()
+	log.Printf("%s stopped watching room %s", observer.ID, room.ID)
+}
+
+// seesHands reports whether the identity may see every hand. The role on the token
+// is checked against the account, as AuthRequired does, so a demoted coach or a
+// token revoked by a password change only gets to watch.
+func seesHands(id identity) bool {
+	if id.Role != models.RoleCoach && id.Role != models.RoleAdmin {
+		return false
+	}
+	if models.DB == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetEnvDuration("DB_TIMEOUT", DefaultDBTimeout))
+	defer cancel()
+
+	var user models.User
+	if err := models.DB.WithContext(ctx).Select("role", "token_version").First(&user, id.UserID).Error; err != nil {
+		log.Printf("Can't check the role of %s: %v", id.UserID, err)
+		return false
+	}
+	if user.TokenVersion != id.TokenVersion {
+		return false
+	}
+	return user.Role == models.RoleCoach || user.Role == models.RoleAdmin
+}
+
+// coachView reports whether the observer gets to see every hand of the room. A coach
+// who took a seat in the room after they started watching is back to the masked view.
+// The caller must hold game.Manager.Mu.
+func coachView(observer *game.Observer, room *game.Room) bool {
+	if !observer.Coach {
+		return false
+	}
+	for _, p := range room.Players {
+		if p.UserID != "" && p.UserID == observer.UserID {
+			return false
+		}
+	}
+	return true
+}
+
+// sendObserverUpdate sends an observer the game_update matching their role
+func sendObserverUpdate(observer *game.Observer, room *game.Room) {
+	state := maskGameStateFor("", room)
+	if coachView(observer, room) {
+		state = coachGameState(room)
+	}
+
+	observer.Conn.WriteJSON(game.WSResponse{
+		Type:    "game_update",
+		Payload: map[string]interface{}{"game": state},
+	})
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"hokm-backend/game"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+)
+
+func TestCoachVisibilityIsCheckedAgainstTheAccount(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+
+	tests := []struct {
+		name   string
+		change func(user *models.User)
+		coach  bool
+	}{
+		{"coach", func(*models.User) {}, true},
+		{"demoted", func(u *models.User) { models.DB.Model(u).Update("role", models.RolePlayer) }, false},
+		{"revoked", func(u *models.User) { models.DB.Model(u).Update("token_version", 1) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, token := newUser(t, tt.name, models.RoleCoach)
+			tt.change(user)
+
+			c := dial(t, srv, "watch="+tb.room.ID+"&token="+token)
+			if got := c.expect(t, MessageObserverJoined)["coach"]; got != tt.coach {
+				t.Fatalf("coach = %v, want %v", got, tt.coach)
+			}
+		})
+	}
+}
+
+func TestCoachCantWatchTheirOwnRoom(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	srv := newTestServer(t)
+
+	user, token := newUser(t, "seated-coach", models.RoleCoach)
+	c := dial(t, srv, "token="+token)
+	c.expect(t, "join_room")
+	room, _ := game.Manager.FindRoomByUserID(fmt.Sprint(user.ID))
+	if room == nil {
+		t.Fatal("the coach wasn't seated")
+	}
+
+	watcher := dial(t, srv, "watch="+room.ID+"&token="+token)
+	var got utils.APIError
+	decode(t, watcher.expect(t, MessageError), &got)
+	if got.Code != utils.CodeAlreadyInGame {
+		t.Fatalf("watching their own room got %+v, want %s", got, utils.CodeAlreadyInGame)
+	}
+	if watcher.received(MessageObserverJoined) {
+		t.Fatal("the seated coach joined as an observer")
+	}
+}
+
+func TestCoachWhoTakesASeatLosesTheCoachView(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	srv := newTestServer(t)
+
+	byID := make(map[string]*testClient)
+	for _, c := range dialAll(t, srv, 3) {
+		byID[c.expect(t, "join_room")["your_id"].(string)] = c
+	}
+	room := tablesOf(t, byID, 1)[0].room
+
+	_, token := newUser(t, "coach", models.RoleCoach)
+	watcher := dial(t, srv, "watch="+room.ID+"&token="+token)
+	if watcher.expect(t, MessageObserverJoined)["coach"] != true {
+		t.Fatal("the coach didn't get the coach view")
+	}
+
+	// The coach takes the last seat of the room they watch
+	seated := dial(t, srv, "token="+token)
+	byID[seated.expect(t, "join_room")["your_id"].(string)] = seated
+	tb := tablesOf(t, byID, 1)[0]
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	batch := watcher.expect(t, "deal_cards_batch_2")
+	if _, ok := batch["cards"]; ok {
+		t.Fatalf("a seated coach still sees the hands through their observer: %v", batch)
+	}
+}
+
+func TestSpectatingARunningRoomDeliversItsState(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	tb.play(t, tb.firstLegal)
+
+	// The room is found through GET /rooms
+	router := gin.New()
+	router.GET("/rooms", ListRooms)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms", nil))
+	var list struct {
+		Rooms []struct {
+			ID      string `json:"id"`
+			Running bool   `json:"running"`
+		} `json:"rooms"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Rooms) != 1 || list.Rooms[0].ID != tb.room.ID || !list.Rooms[0].Running {
+		t.Fatalf("GET /rooms = %s, want %s running", rec.Body, tb.room.ID)
+	}
+
+	watcher := dial(t, srv, "room_id="+tb.room.ID+"&role=spectator")
+	watcher.expect(t, MessageObserverJoined)
+	update := watcher.expect(t, "game_update")
+	var state struct {
+		Game struct {
+			TrumpSuit       string        `json:"trump_suit"`
+			CurrentPlayerID string        `json:"current_player_id"`
+			CurrentTrick    []interface{} `json:"current_trick"`
+		} `json:"game"`
+	}
+	decode(t, update, &state)
+	game.Manager.Mu.RLock()
+	current := tb.room.Game.CurrentPlayerID
+	game.Manager.Mu.RUnlock()
+	if state.Game.TrumpSuit != "hearts" || state.Game.CurrentPlayerID != current || len(state.Game.CurrentTrick) != 1 {
+		t.Fatalf("game_update = %v, want hearts trump, %s to play and one card on the table", update, current)
+	}
+	if leaks := leakedHands(update, ""); len(leaks) > 0 {
+		t.Fatalf("the spectator saw the hands of %v", leaks)
+	}
+}
+
+func TestSpectatingARoomThatHasntStartedIsRejected(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 2)
+	watcher := dial(t, srv, "room_id="+tb.room.ID+"&role=spectator")
+	var got utils.APIError
+	decode(t, watcher.expect(t, MessageError), &got)
+	if got.Code != utils.CodeWrongPhase {
+		t.Fatalf("spectating a lobby got %+v, want %s", got, utils.CodeWrongPhase)
+	}
+}
+
+func TestSpectatorSeesTheDealWithoutTheCards(t *testing.T) {
+	fastGame(t)
+	t.Setenv("TRUMP_SELECTION", game.TrumpSelectionAce)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	watcher := dial(t, srv, "watch="+tb.room.ID)
+	watcher.expect(t, MessageObserverJoined)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	if drawn := watcher.expect(t, "dealing_card"); drawn["player_id"] == nil || drawn["card"] == nil {
+		t.Fatalf("dealing_card = %v, want the drawn card and who drew it", drawn)
+	}
+	if selected := watcher.expect(t, "trump_player_selected"); selected["trump_player_id"] == nil {
+		t.Fatalf("trump_player_selected = %v, want the Trump Player", selected)
+	}
+
+	// Every batch tells how many cards each player got, and those add up to the hands.
+	// The Trump Player's first cards came with their trump prompt.
+	game.Manager.Mu.RLock()
+	pattern, trumpID := tb.room.Settings.DealBatches(), tb.room.Game.TrumpPlayer.ID
+	game.Manager.Mu.RUnlock()
+	dealt := make(map[string]float64)
+	for n := 1; n <= len(pattern); n++ {
+		batch := watcher.expect(t, fmt.Sprint("deal_cards_batch_", n))
+		if _, ok := batch["cards"]; ok {
+			t.Fatalf("deal_cards_batch_%d showed the spectator the cards: %v", n, batch)
+		}
+		counts, _ := batch["counts"].(map[string]interface{})
+		for id, count := range counts {
+			dealt[id] += count.(float64)
+		}
+	}
+	for _, id := range tb.ids {
+		want := 13
+		if id == trumpID {
+			want -= pattern[0]
+		}
+		if dealt[id] != float64(want) {
+			t.Errorf("the spectator was told %s got %v cards, want %d", id, dealt[id], want)
+		}
+	}
+	for _, typ := range watcher.types() {
+		for _, payload := range watcher.all(typ) {
+			if leaks := leakedHands(payload, ""); len(leaks) > 0 {
+				t.Fatalf("%s showed the spectator the hands of %v", typ, leaks)
+			}
+		}
+	}
+}
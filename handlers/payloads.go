@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"hokm-backend/game"
+	"hokm-backend/utils"
+	"log"
+)
+
+// PlayCardData is the data of play_card, e.g. {"suit":"hearts","rank":"Q","move_id":"m-17"}.
+// The value follows from the rank and may be left out; when sent it has to match.
+// The optional move_id makes a resent play_card a no-op.
+// Keys match case-insensitively, so clients still sending the old {"Suit":...} form
+// keep working while they move to lowercase. choose_trump, reaction and vote_kick
+// take a plain string, replay a number and rematch an optional bool.
+type PlayCardData struct {
+	Suit   string `json:"suit"`
+	Rank   string `json:"rank"`
+	Value  int    `json:"value"`
+	MoveID string `json:"move_id"`
+}
+
+// card checks the fields against the deck and returns the card they describe,
+// valued by the room's card order
+func (d PlayCardData) card(order string) (game.Card, error) {
+	if !game.IsValidSuit(d.Suit) {
+		return game.Card{}, errors.New("Invalid suit")
+	}
+	if !game.IsValidRank(d.Rank) {
+		return game.Card{}, errors.New("Invalid rank")
+	}
+	card, err := game.NewOrderedCard(d.Suit, d.Rank, order)
+	if err != nil {
+		return game.Card{}, err
+	}
+	if d.Value != 0 && d.Value != card.Value {
+		return game.Card{}, errors.New("Invalid value for rank")
+	}
+	return card, nil
+}
+
+// decodeData decodes the message's data into v. Missing or mistyped data is answered
+// with a bad_request error and reported as false.
+func decodeData(player *game.Player, msg game.WSMessage, v interface{}) bool {
+	if len(msg.Data) == 0 || string(msg.Data) == "null" {
+		sendError(player.Conn, utils.CodeBadRequest, "Missing data for "+msg.Action)
+		return false
+	}
+	if err := json.Unmarshal(msg.Data, v); err != nil {
+		log.Printf("Malformed %s data from %s: %v", msg.Action, player.ID, err)
+		sendError(player.Conn, utils.CodeBadRequest, "Invalid data for "+msg.Action)
+		return false
+	}
+	return true
+}
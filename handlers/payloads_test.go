@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+// expectBadRequest sends the action with the data and expects it rejected as bad_request
+func expectBadRequest(t *testing.T, c *testClient, action string, data interface{}) {
+	t.Helper()
+	c.send(t, action, data)
+	var got utils.APIError
+	decode(t, c.expect(t, MessageError), &got)
+	if got.Code != utils.CodeBadRequest {
+		t.Fatalf("%s with data %v got %+v, want %s", action, data, got, utils.CodeBadRequest)
+	}
+}
+
+func TestMalformedActionDataIsABadRequest(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	lobby := []struct {
+		action string
+		data   interface{}
+	}{
+		{"swap_seat", nil},
+		{"swap_seat", 3},
+		{"reaction", nil},
+		{"reaction", []string{"gg"}},
+		{"team_chat", map[string]string{"text": "hi"}},
+		{"vote_kick", true},
+		{"replay", "12"},
+		{"replay", -1},
+	}
+	for _, tt := range lobby {
+		expectBadRequest(t, tb.clients[0], tt.action, tt.data)
+	}
+
+	tb.startGame(t)
+	var trumpID string
+	game.Manager.Mu.RLock()
+	trumpID = tb.room.Game.TrumpPlayer.ID
+	game.Manager.Mu.RUnlock()
+	expectBadRequest(t, tb.client(t, trumpID), "choose_trump", nil)
+	expectBadRequest(t, tb.client(t, trumpID), "choose_trump", map[string]string{"suit": "hearts"})
+
+	tb.chooseTrump(t, "hearts")
+	c := tb.client(t, trumpID)
+	expectBadRequest(t, c, "play_card", nil)
+	expectBadRequest(t, c, "play_card", "hearts Q")
+	expectBadRequest(t, c, "play_card", map[string]interface{}{"suit": "hearts", "rank": 12})
+	expectBadRequest(t, c, "play_card", map[string]interface{}{"suit": "hearts", "rank": "Q", "value": "12"})
+}
+
+func TestHandCountsDropAsCardsArePlayed(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+
+	watcher := tb.clients[3]
+	want := make(map[string]int)
+	for _, id := range tb.ids {
+		want[id] = 13
+	}
+	for played := 1; played <= 3; played++ {
+		game.Manager.Mu.RLock()
+		player := tb.room.Game.CurrentPlayer().ID
+		game.Manager.Mu.RUnlock()
+		tb.play(t, tb.firstLegal)
+		want[player]--
+
+		// Skip to the game_update of this play
+		var update struct {
+			Game struct {
+				HandCounts map[string]int `json:"hand_counts"`
+			} `json:"game"`
+		}
+		_, ok := watcher.next(func(r game.WSResponse) bool {
+			if r.Type != "game_update" {
+				return false
+			}
+			decode(t, r.Payload, &update)
+			total := 0
+			for _, n := range update.Game.HandCounts {
+				total += n
+			}
+			return total == 52-played
+		})
+		if !ok {
+			t.Fatalf("no game_update with %d cards in hand after %d plays; got %v", 52-played, played, watcher.types())
+		}
+		for id, n := range want {
+			if update.Game.HandCounts[id] != n {
+				t.Fatalf("hand_counts after %d plays = %v, want %v", played, update.Game.HandCounts, want)
+			}
+		}
+	}
+}
+
+func TestOversizedMessageClosesOnlyThatConnection(t *testing.T) {
+	fastGame(t)
+	t.Setenv("WS_READ_LIMIT", "512")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.clients[1].send(t, "team_chat", strings.Repeat("x", 1024))
+	if code := tb.clients[1].closeCode(t); code != websocket.CloseMessageTooBig {
+		t.Fatalf("oversized message closed the connection with %d, want %d", code, websocket.CloseMessageTooBig)
+	}
+	waitFor(t, "the sender to disconnect", func() bool {
+		for _, p := range tb.room.Players {
+			if p.ID == tb.ids[1] {
+				return !p.Connected
+			}
+		}
+		return false
+	})
+
+	// Everyone else keeps playing on
+	tb.clients[0].send(t, "ready", nil)
+	for _, seat := range []int{0, 2, 3} {
+		expectReadyFrom(t, tb.clients[seat], 4)
+	}
+}
+
+func TestPlayingOutOfTurnNamesThePlayerOnTurn(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	game.Manager.Mu.RLock()
+	onTurn := tb.room.Game.CurrentPlayerID
+	var waiting *game.Player
+	for _, p := range tb.room.Game.Players {
+		if p.ID != onTurn {
+			waiting = p
+			break
+		}
+	}
+	card := waiting.Hand[0]
+	game.Manager.Mu.RUnlock()
+
+	c := tb.client(t, waiting.ID)
+	c.send(t, "play_card", map[string]interface{}{"suit": card.Suit, "rank": card.Rank})
+	got := c.expect(t, MessageOutOfTurn)
+	if got["current_player"] != onTurn {
+		t.Fatalf("out_of_turn = %v, want %s on turn", got, onTurn)
+	}
+	if c.received(MessageError) {
+		t.Fatal("an out-of-turn play also got a generic error")
+	}
+}
+
+func TestGarbageJSONIsABadRequestAndKeepsTheConnection(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	for _, garbage := range []string{"{not json", "null}", `["play_card"]`} {
+		if err := tb.clients[1].ws.WriteMessage(websocket.TextMessage, []byte(garbage)); err != nil {
+			t.Fatal(err)
+		}
+		var got utils.APIError
+		decode(t, tb.clients[1].expect(t, MessageError), &got)
+		if got.Code != utils.CodeBadRequest {
+			t.Fatalf("%q got %+v, want %s", garbage, got, utils.CodeBadRequest)
+		}
+	}
+
+	// The sender is still seated, connected and heard
+	game.Manager.Mu.RLock()
+	for _, p := range tb.room.Players {
+		if p.ID == tb.ids[1] && !p.Connected {
+			t.Error("the sender of garbage was disconnected")
+		}
+	}
+	game.Manager.Mu.RUnlock()
+	tb.clients[1].send(t, "ready", nil)
+	expectReadyFrom(t, tb.clients[0], 4)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import "hokm-backend/game"
+
+const (
+	MessageLastTrick   = "last_trick"
+	MessagePeekExpired = "peek_expired"
+)
+
+// handlePeekLastTrick shows the player the trick that was just completed, as long
+// as the peek window is open and nobody has led the next card
+func handlePeekLastTrick(player *game.Player, room *game.Room) {
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+
+	trick, ok := room.Game.LastTrick()
+	if !ok {
+		player.Send(game.WSResponse{
+			Type: MessagePeekExpired,
+			Payload: map[string]interface{}{
+				"message": "The last trick can no longer be reviewed.",
+			},
+		})
+		return
+	}
+
+	player.Send(game.WSResponse{
+		Type: MessageLastTrick,
+		Payload: map[string]interface{}{
+			"cards":      trick.Cards,
+			"player_ids": trick.PlayerIDs,
+			"winner_id":  trick.WinnerID,
+		},
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+// playFirstTrick plays one full trick with legal cards and returns its record
+func (tb *table) playFirstTrick(t *testing.T) game.TrickRecord {
+	t.Helper()
+	for i := 0; i < len(tb.clients); i++ {
+		tb.play(t, tb.firstLegal)
+	}
+	waitFor(t, "the trick to complete", func() bool {
+		g := tb.room.Game
+		return len(g.RoundTricks) == 1 && len(g.CurrentTrick) == 0
+	})
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	return tb.room.Game.RoundTricks[0]
+}
+
+func TestPeekLastTrick(t *testing.T) {
+	tests := []struct {
+		name    string
+		after   func(t *testing.T, tb *table)
+		expired bool
+	}{
+		{"within the window", func(*testing.T, *table) {}, false},
+		{"after the next lead", func(t *testing.T, tb *table) { tb.play(t, tb.firstLegal) }, true},
+		{"after the window", func(t *testing.T, tb *table) {
+			game.Manager.Mu.Lock()
+			tb.room.Game.LastTrickAt = time.Now().Add(-game.PeekWindow - time.Second)
+			game.Manager.Mu.Unlock()
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fastGame(t)
+			srv := newTestServer(t)
+
+			tb := joinTable(t, srv, 4)
+			tb.startGame(t)
+			tb.chooseTrump(t, "hearts")
+			trick := tb.playFirstTrick(t)
+			tt.after(t, tb)
+
+			c := tb.clients[0]
+			c.send(t, "peek_last_trick", nil)
+			if tt.expired {
+				c.expect(t, MessagePeekExpired)
+				if c.received(MessageLastTrick) {
+					t.Fatal("got the last trick after the peek expired")
+				}
+				return
+			}
+
+			var got game.TrickRecord
+			decode(t, c.expect(t, MessageLastTrick), &got)
+			if got.WinnerID != trick.WinnerID || len(got.Cards) != 4 || len(got.PlayerIDs) != 4 {
+				t.Fatalf("last_trick = %+v, want %+v", got, trick)
+			}
+			for i := range got.Cards {
+				if got.Cards[i] != trick.Cards[i] || got.PlayerIDs[i] != trick.PlayerIDs[i] {
+					t.Fatalf("last_trick = %+v, want %+v", got, trick)
+				}
+			}
+		})
+	}
+}
+
+func TestRoundTricksKeepEveryTrickWithItsWinner(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "diamonds")
+	for i := 0; i < 12; i++ {
+		tb.play(t, tb.firstLegal)
+	}
+	waitFor(t, "the third trick to complete", func() bool { return len(tb.room.Game.RoundTricks) == 3 })
+
+	watcher := tb.clients[0]
+	var winners []string
+	for i := 0; i < 3; i++ {
+		winners = append(winners, watcher.expect(t, "trick_complete")["winner_id"].(string))
+	}
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	for i, trick := range tb.room.Game.RoundTricks {
+		if trick.WinnerID != winners[i] || len(trick.Cards) != 4 || len(trick.PlayerIDs) != 4 {
+			t.Fatalf("trick %d = %+v, want 4 cards won by %s", i+1, trick, winners[i])
+		}
+		if i > 0 && trick.PlayerIDs[0] != winners[i-1] {
+			t.Fatalf("trick %d was led by %s, want the winner of the trick before, %s", i+1, trick.PlayerIDs[0], winners[i-1])
+		}
+	}
+}
+
+func TestOnlyTheLastTrickOfARoundIsFlagged(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "clubs")
+	// Out of reach, so the Round runs until the hands are empty
+	game.Manager.Mu.Lock()
+	tb.room.Game.TricksToWinRound = 14
+	game.Manager.Mu.Unlock()
+	for tb.playing() {
+		tb.play(t, tb.firstLegal)
+	}
+
+	watcher := tb.clients[1]
+	watcher.expect(t, "round_winner")
+	completed := watcher.all("trick_complete")
+	if len(completed) != 13 {
+		t.Fatalf("%d tricks completed, want 13", len(completed))
+	}
+	for i, trick := range completed {
+		if last := i == 12; trick["is_last_trick_of_round"] != last {
+			t.Fatalf("trick %d has is_last_trick_of_round %v, want %v", i+1, trick["is_last_trick_of_round"], last)
+		}
+	}
+	tb.awaitSecondRound(t)
+}
+
+func TestTrickWinnerLeadsWhenTheRosterIsOrderedDifferently(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	// The room's roster no longer follows the seats the game's turns go by
+	game.Manager.Mu.Lock()
+	players := tb.room.Players
+	for i, j := 0, len(players)-1; i < j; i, j = i+1, j-1 {
+		players[i], players[j] = players[j], players[i]
+	}
+	game.Manager.Mu.Unlock()
+
+	trick := tb.playFirstTrick(t)
+	game.Manager.Mu.RLock()
+	leader := tb.room.Game.CurrentPlayerID
+	game.Manager.Mu.RUnlock()
+	if leader != trick.WinnerID {
+		t.Fatalf("%s leads the second trick, want the winner %s", leader, trick.WinnerID)
+	}
+
+	tb.play(t, tb.firstLegal)
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	g := tb.room.Game
+	var next string
+	for i, p := range g.Players {
+		if p.ID == leader {
+			next = g.Players[(i+1)%len(g.Players)].ID
+		}
+	}
+	if g.CurrentPlayerID != next {
+		t.Fatalf("%s plays after the leader, want the next seat %s", g.CurrentPlayerID, next)
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"log"
+	"time"
+)
+
+const MessagePlayerReaction = "player_reaction"
+
+// ReactionCooldown is the minimum time between two reactions from the same player
+const ReactionCooldown = 2 * time.Second
+
+// Predefined reactions clients can send; anything else is rejected
+var allowedReactions = map[string]bool{
+	"nice":        true,
+	"wow":         true,
+	"oops":        true,
+	"thanks":      true,
+	"well_played": true,
+	"hurry_up":    true,
+}
+
+// Reactions can be switched off on their own with REACTIONS_ENABLED=false
+func reactionsEnabled() bool {
+	return config.GetEnv("REACTIONS_ENABLED", "true") != "false"
+}
+
+func handleReaction(player *game.Player, room *game.Room, msg game.WSMessage) {
+	if !reactionsEnabled() {
+		log.Println("Reactions are disabled")
+		return
+	}
+
+	var code string
+	if !decodeData(player, msg, &code) {
+		return
+	}
+	if !allowedReactions[code] {
+		log.Printf("Rejected reaction from %s: %s", player.ID, code)
+		return
+	}
+
+	game.Manager.Mu.Lock()
+	if time.Since(player.LastReactionAt) < ReactionCooldown {
+		game.Manager.Mu.Unlock()
+		log.Printf("Reaction from %s dropped by rate limit", player.ID)
+		return
+	}
+	player.LastReactionAt = time.Now()
+	var recipients []*game.Player
+	for _, p := range room.Players {
+		if p.Connected {
+			recipients = append(recipients, p)
+		}
+	}
+	game.Manager.Mu.Unlock()
+
+	for _, p := range recipients {
+		p.Send(game.WSResponse{
+			Type: MessagePlayerReaction,
+			Payload: map[string]interface{}{
+				"player_id": player.ID,
+				"reaction":  code,
+			},
+		})
+	}
+}
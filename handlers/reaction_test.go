@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"testing"
+)
+
+// reactionsSeen lists the reactions c received so far
+func reactionsSeen(c *testClient) []string {
+	var codes []string
+	for _, p := range c.all(MessagePlayerReaction) {
+		codes = append(codes, p["reaction"].(string))
+	}
+	return codes
+}
+
+func TestReactionAllowlistAndRateLimit(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	sender, other := tb.clients[0], tb.clients[1]
+
+	sender.send(t, "reaction", "lol")
+	sender.send(t, "reaction", "<script>")
+	sender.send(t, "reaction", "nice")
+	sender.send(t, "reaction", "wow") // Inside the cooldown of nice
+	sender.send(t, "ready", nil)
+	expectReadyFrom(t, other, 4)
+
+	got := other.all(MessagePlayerReaction)
+	if len(got) != 1 || got[0]["reaction"] != "nice" || got[0]["player_id"] != tb.ids[0] {
+		t.Fatalf("got reactions %v, want only nice from %s", got, tb.ids[0])
+	}
+}
+
+func TestReactionsSwitchOffWithoutChat(t *testing.T) {
+	fastGame(t)
+	t.Setenv("REACTIONS_ENABLED", "false")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	sender := tb.clients[0]
+	partners, _ := tb.teams(t, tb.ids[0])
+
+	sender.send(t, "reaction", "nice")
+	sender.send(t, "team_chat", "still here")
+	partners[0].expect(t, MessageTeamChat)
+	if codes := reactionsSeen(partners[0]); len(codes) != 0 {
+		t.Fatalf("got reactions %v with reactions switched off", codes)
+	}
+}
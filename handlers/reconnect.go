@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"math"
+	"time"
+)
+
+const (
+	MessageReconnectCountdown = "reconnect_countdown"
+	MessageReconnectCancelled = "reconnect_cancelled"
+)
+
+// DefaultReconnectCountdownInterval is how often the table is told how long a disconnected
+// player has left, unless RECONNECT_COUNTDOWN_INTERVAL says otherwise
+const DefaultReconnectCountdownInterval = 5 * time.Second
+
+// armReconnectCountdown starts tracking the player's reconnect window and returns its
+// context, which handleReconnectingPlayer cancels. The caller must hold game.Manager.Mu.
+func armReconnectCountdown(player *game.Player) context.Context {
+	if player.CancelReconnect != nil {
+		player.CancelReconnect()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	player.CancelReconnect = cancel
+	return ctx
+}
+
+// waitForReconnect broadcasts reconnect_countdown every RECONNECT_COUNTDOWN_INTERVAL
+// until timeout passes. It reports false if the player came back first.
+func waitForReconnect(ctx context.Context, player *game.Player, timeout time.Duration) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	interval := config.GetEnvDuration("RECONNECT_COUNTDOWN_INTERVAL", DefaultReconnectCountdownInterval)
+	if interval <= 0 {
+		interval = DefaultReconnectCountdownInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	end := time.Now().Add(timeout)
+	broadcastReconnectCountdown(player, time.Until(end))
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline.C:
+			return true
+		case <-ticker.C:
+			broadcastReconnectCountdown(player, time.Until(end))
+		}
+	}
+}
+
+// cancelReconnectCountdown stops the countdown of a returning player and tells the
+// table. The caller must hold game.Manager.Mu.
+func cancelReconnectCountdown(player *game.Player, room *game.Room) {
+	if player.CancelReconnect == nil {
+		return
+	}
+	player.CancelReconnect()
+	player.CancelReconnect = nil
+
+	for _, p := range room.Players {
+		if p.ID != player.ID && p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageReconnectCancelled,
+				Payload: map[string]interface{}{
+					"player_id": player.ID,
+				},
+			})
+		}
+	}
+}
+
+func broadcastReconnectCountdown(player *game.Player, left time.Duration) {
+	room := findPlayerRoom(player)
+	if room == nil {
+		return
+	}
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	for _, p := range room.Players {
+		if p.ID != player.ID && p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageReconnectCountdown,
+				Payload: map[string]interface{}{
+					"player_id":    player.ID,
+					"seconds_left": int(math.Ceil(left.Seconds())),
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+)
+
+func TestReconnectingTrumpPlayerIsPromptedAgain(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	srv := newTestServer(t)
+
+	tb, tokens := joinAccountTable(t, srv, "trump", 4)
+	tb.startGame(t)
+	game.Manager.Mu.RLock()
+	trumpID := tb.room.Game.TrumpPlayer.ID
+	game.Manager.Mu.RUnlock()
+
+	// The Trump Player drops before answering the prompt
+	trumpPlayer := tb.client(t, trumpID)
+	trumpPlayer.expect(t, "choose_trump")
+	trumpPlayer.ws.Close()
+	waitFor(t, "the Trump Player to disconnect", func() bool { return !tb.room.Game.TrumpPlayer.Connected })
+
+	back := dial(t, srv, "token="+tokens[trumpID])
+	var prompt struct {
+		Cards []game.Card `json:"cards"`
+	}
+	decode(t, back.expect(t, "choose_trump"), &prompt)
+	if len(prompt.Cards) != 5 {
+		t.Fatalf("the re-prompt offered %v, want the first 5 cards", prompt.Cards)
+	}
+
+	back.send(t, "choose_trump", prompt.Cards[0].Suit)
+	back.expect(t, "turn_update")
+	waitFor(t, "play to begin", func() bool { return tb.room.Game.Phase == game.PhasePlaying })
+}
+
+func TestASecondConnectionOfASeatedAccountIsRejected(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	srv := newTestServer(t)
+
+	tb, tokens := joinAccountTable(t, srv, "twice", 4)
+	tb.startGame(t)
+	seated := tb.ids[2]
+
+	second := dial(t, srv, "token="+tokens[seated])
+	var got utils.APIError
+	decode(t, second.expect(t, MessageError), &got)
+	if got.Code != utils.CodeAlreadyInGame {
+		t.Fatalf("second connection got %+v, want %s", got, utils.CodeAlreadyInGame)
+	}
+	if code := second.closeCode(t); code != CloseAlreadyInGame {
+		t.Fatalf("second connection closed with %d, want %d", code, CloseAlreadyInGame)
+	}
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	if len(game.Manager.Rooms) != 1 || len(tb.room.Players) != 4 {
+		t.Fatalf("%d rooms with %d players after the rejection, want the one full table", len(game.Manager.Rooms), len(tb.room.Players))
+	}
+	for _, p := range tb.room.Players {
+		if p.ID == seated && !p.Connected {
+			t.Fatal("the rejected connection disconnected the seated player")
+		}
+	}
+}
+
+func TestReconnectCountdownStopsWhenThePlayerReturns(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	const interval = 20 * time.Millisecond
+	t.Setenv("RECONNECT_COUNTDOWN_INTERVAL", interval.String())
+	srv := newTestServer(t)
+
+	tb, tokens := joinAccountTable(t, srv, "countdown", 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	gone := tb.dropSeat(t, 1)
+
+	watcher := tb.clients[0]
+	for i := 0; i < 2; i++ {
+		if tick := watcher.expect(t, MessageReconnectCountdown); tick["player_id"] != gone.ID {
+			t.Fatalf("reconnect_countdown = %v, want the countdown of %s", tick, gone.ID)
+		}
+	}
+
+	dial(t, srv, "token="+tokens[gone.ID]).expect(t, "game_state")
+	if cancelled := watcher.expect(t, MessageReconnectCancelled); cancelled["player_id"] != gone.ID {
+		t.Fatalf("reconnect_cancelled = %v, want %s", cancelled, gone.ID)
+	}
+	waitFor(t, "the countdown to be cleared", func() bool {
+		for _, p := range tb.room.Players {
+			if p.ID == gone.ID {
+				return p.Connected && p.CancelReconnect == nil
+			}
+		}
+		return false
+	})
+
+	ticks := len(watcher.all(MessageReconnectCountdown))
+	time.Sleep(5 * interval)
+	if n := len(watcher.all(MessageReconnectCountdown)); n != ticks {
+		t.Fatalf("%d more reconnect_countdown after the player returned", n-ticks)
+	}
+}
+
+func TestReconnectMidTrickGetsTheTrickInPlayOrder(t *testing.T) {
+	fastGame(t)
+	testDB(t)
+	srv := newTestServer(t)
+
+	tb, tokens := joinAccountTable(t, srv, "midtrick", 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	type play struct {
+		PlayerID string    `json:"player_id"`
+		Card     game.Card `json:"card"`
+	}
+	var played []play
+	for i := 0; i < 2; i++ {
+		tb.play(t, func(p *game.Player) game.Card {
+			c := tb.firstLegal(p)
+			played = append(played, play{p.ID, c})
+			return c
+		})
+	}
+
+	// The player on turn drops and comes back before the trick is finished
+	game.Manager.Mu.RLock()
+	next := tb.room.Game.CurrentPlayerID
+	game.Manager.Mu.RUnlock()
+	seat := 0
+	for i, id := range tb.ids {
+		if id == next {
+			seat = i
+		}
+	}
+	tb.dropSeat(t, seat)
+
+	var state struct {
+		TrickPlays []play `json:"trick_plays"`
+	}
+	decode(t, dial(t, srv, "token="+tokens[next]).expect(t, MessageGameState), &state)
+	if len(state.TrickPlays) != len(played) {
+		t.Fatalf("trick_plays = %+v, want %+v", state.TrickPlays, played)
+	}
+	for i := range played {
+		if state.TrickPlays[i] != played[i] {
+			t.Fatalf("trick_plays = %+v, want %+v", state.TrickPlays, played)
+		}
+	}
+}
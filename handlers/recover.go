@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"log"
+	"runtime/debug"
+)
+
+// goSafe runs fn in its own goroutine, logging a panic instead of taking down the process
+func goSafe(name string, fn func()) {
+	go func() {
+		defer recoverPanic(name)
+		fn()
+	}()
+}
+
+// recoverPanic must be deferred directly; it logs a recovered panic with its stack
+func recoverPanic(name string) {
+	if r := recover(); r != nil {
+		log.Printf("🔥 Panic in %s: %v\n%s", name, r, debug.Stack())
+	}
+}
+
+// recoverPlayerPanic must be deferred directly by a player's read loop. It logs the
+// panic with the player's room and marks the player as disconnected.
+func recoverPlayerPanic(player *game.Player) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	roomID := ""
+	if room := findPlayerRoom(player); room != nil {
+		roomID = room.ID
+	}
+	log.Printf("🔥 Panic handling player %s in room %q: %v\n%s", player.ID, roomID, r, debug.Stack())
+
+	if player.Connected {
+		unregisterPlayer(player)
+	}
+}
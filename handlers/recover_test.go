@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+func TestPanicHandlingAPlayerIsContained(t *testing.T) {
+	fastGame(t)
+	room := game.NewRoom(game.DefaultRoomSettings())
+	room.Settings.ReconnectTimeout = 10 * time.Millisecond
+	player := &game.Player{ID: "p1", Connected: true}
+	room.Players = append(room.Players, player)
+	game.Manager.Mu.Lock()
+	game.Manager.Rooms[room.ID] = room
+	game.Manager.Mu.Unlock()
+
+	func() {
+		defer recoverPlayerPanic(player)
+		panic("bad message")
+	}()
+
+	game.Manager.Mu.RLock()
+	connected := player.Connected
+	game.Manager.Mu.RUnlock()
+	if connected {
+		t.Fatal("the player is still connected after their handler panicked")
+	}
+}
+
+func TestGoSafeRecoversAPanic(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	goSafe("test", func() {
+		defer wg.Done()
+		panic("broadcast failed")
+	})
+	wg.Wait()
+}
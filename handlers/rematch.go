@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"log"
+)
+
+const (
+	MessageRematchUpdate    = "rematch_update"
+	MessageRematchStart     = "rematch_start"
+	MessageRematchCancelled = "rematch_cancelled"
+)
+
+// handleRematch records a player's answer to a rematch after the match is over.
+// Sending false declines; anything else opts in. Once every seat opts in,
+// the game is reset for the same seats and teams and dealt again.
+func handleRematch(player *game.Player, room *game.Room, msg game.WSMessage) {
+	if over, _ := room.Game.IsMatchOver(); !over {
+		log.Printf("Player %s asked for a rematch before the match ended", player.ID)
+		return
+	}
+
+	// Data is optional here; only an explicit false declines
+	accept := true
+	if len(msg.Data) > 0 && !decodeData(player, msg, &accept) {
+		return
+	}
+
+	game.Manager.Mu.Lock()
+	if !accept {
+		cancelRematch(room, player.Name+" declined the rematch.")
+		game.Manager.Mu.Unlock()
+		return
+	}
+
+	if room.RematchVotes == nil {
+		room.RematchVotes = make(map[string]bool)
+	}
+	room.RematchVotes[player.ID] = true
+
+	accepted := []string{}
+	for _, p := range room.Players {
+		if room.RematchVotes[p.ID] {
+			accepted = append(accepted, p.ID)
+		}
+	}
+
+	start := len(room.Players) == room.Settings.Seats() && len(accepted) == len(room.Players)
+	if start {
+		room.ResetGame()
+		room.Started = true
+	}
+	game.Manager.Mu.Unlock()
+
+	if !start {
+		broadcastToRoom(room, MessageRematchUpdate, map[string]interface{}{
+			"accepted": accepted,
+			"needed":   room.Settings.Seats(),
+		})
+		return
+	}
+
+	log.Printf("Rematch starting in room %s", room.ID)
+	broadcastToRoom(room, MessageRematchStart, map[string]interface{}{
+		"room_id": room.ID,
+	})
+	goSafe("dealing in room "+room.ID, func() { initializeGame(room) })
+}
+
+// cancelRematch drops all rematch answers and tells the room why. The caller must
+// hold game.Manager.Mu.
+func cancelRematch(room *game.Room, reason string) {
+	if room == nil || len(room.RematchVotes) == 0 {
+		return
+	}
+	room.RematchVotes = nil
+
+	broadcastToRoom(room, MessageRematchCancelled, map[string]interface{}{
+		"message": reason,
+	})
+}
+
+// broadcastToRoom sends a message to every connected player in the room
+func broadcastToRoom(room *game.Room, messageType string, payload map[string]interface{}) {
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type:    messageType,
+				Payload: payload,
+			})
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestFourRematchOptInsStartAFreshGame(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	tb.play(t, tb.firstLegal)
+
+	game.Manager.Mu.RLock()
+	old := tb.room.Game
+	teams := make(map[string]string)
+	for _, p := range tb.room.Players {
+		teams[p.ID] = p.Team
+	}
+	game.Manager.Mu.RUnlock()
+
+	forfeitGame(tb.room, "team2")
+	for _, c := range tb.clients {
+		c.expect(t, "game_over")
+	}
+
+	for i, c := range tb.clients {
+		c.send(t, "rematch", true)
+		if i < len(tb.clients)-1 {
+			if update := tb.clients[0].expect(t, MessageRematchUpdate); update["needed"] != 4.0 {
+				t.Fatalf("rematch_update = %v, want 4 needed", update)
+			}
+		}
+	}
+	for _, c := range tb.clients {
+		c.expect(t, MessageRematchStart)
+	}
+
+	waitFor(t, "the rematch deal", func() bool {
+		return tb.room.Game != old && tb.room.Game.Phase == game.PhaseWaitingTrump
+	})
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	g := tb.room.Game
+	if g.RoundScores["team1"] != 0 || g.RoundScores["team2"] != 0 || len(g.Moves) != 0 {
+		t.Fatalf("rematch starts at %v with %d moves, want a fresh game", g.RoundScores, len(g.Moves))
+	}
+	if len(tb.room.Players) != 4 {
+		t.Fatalf("rematch has %d players, want the same 4", len(tb.room.Players))
+	}
+	for _, p := range tb.room.Players {
+		if team, ok := teams[p.ID]; !ok || team != p.Team {
+			t.Fatalf("player %s is on %s in the rematch, want the same seats and teams", p.ID, p.Team)
+		}
+	}
+}
+
+func TestFourRequeuesFormANewGame(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	forfeitGame(tb.room, "team2")
+	for _, c := range tb.clients {
+		c.expect(t, "game_over")
+	}
+
+	for i, c := range tb.clients[:3] {
+		c.send(t, "requeue", nil)
+		if queued := c.expect(t, MessageQueued); queued["position"] != float64(i+1) {
+			t.Fatalf("queued = %v, want position %d", queued, i+1)
+		}
+	}
+	tb.clients[3].send(t, "requeue", nil)
+
+	byID := make(map[string]*testClient)
+	for i, c := range tb.clients {
+		join := c.expect(t, "join_room")
+		if join["room_id"] == tb.room.ID {
+			t.Fatalf("seat %d was put back in the finished room", i)
+		}
+		byID[tb.ids[i]] = c
+	}
+	next := tablesOf(t, byID, 1)[0]
+	if next.room == tb.room {
+		t.Fatal("the finished room is still open")
+	}
+	for i, id := range next.ids {
+		if id != tb.ids[i] {
+			t.Fatalf("new table seats %v, want %v in the order they queued", next.ids, tb.ids)
+		}
+	}
+	next.startGame(t)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import "hokm-backend/game"
+
+const MessageReplayGap = "replay_gap"
+
+// handleReplay resends the messages of the room's EventLog sent to the player's seat
+// after the given seq.
+// If some of them are no longer kept the client is told to resync instead.
+func handleReplay(player *game.Player, msg game.WSMessage) {
+	var since uint64
+	if !decodeData(player, msg, &since) || player.Events == nil {
+		return
+	}
+
+	events, ok := player.Events.Since(player.ID, since)
+	if !ok {
+		player.Send(game.WSResponse{
+			Type: MessageReplayGap,
+			Payload: map[string]interface{}{
+				"message": "Too many messages missed to replay. Resync with get_hand.",
+			},
+		})
+		return
+	}
+	player.Conn.Replay(events)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestRoomMessagesShareOneSequenceAndReplay(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	watcher := dial(t, srv, "watch="+tb.room.ID)
+	watcher.expect(t, MessageObserverJoined)
+	tb.chooseTrump(t, "hearts")
+	watcher.expect(t, "deal_cards_batch_3")
+	watcher.expect(t, "game_update")
+
+	seqs := make(map[uint64]bool)
+	for _, c := range append(tb.clients, watcher) {
+		c.mu.Lock()
+		msgs := append([]game.WSResponse(nil), c.msgs...)
+		c.mu.Unlock()
+
+		var last uint64
+		for _, m := range msgs {
+			if m.Seq == 0 {
+				continue
+			}
+			if m.Seq <= last || m.PrevSeq != last {
+				t.Fatalf("%s: seq %d after %d with prev_seq %d, want an increasing chain", m.Type, m.Seq, last, m.PrevSeq)
+			}
+			if seqs[m.Seq] {
+				t.Fatalf("seq %d was sent to two clients", m.Seq)
+			}
+			seqs[m.Seq] = true
+			last = m.Seq
+		}
+	}
+
+	// Asking for everything after the first numbered message sends the rest again
+	c := tb.clients[0]
+	c.mu.Lock()
+	var numbered []game.WSResponse
+	for _, m := range c.msgs {
+		if m.Seq != 0 {
+			numbered = append(numbered, m)
+		}
+	}
+	c.seen = len(c.msgs)
+	c.mu.Unlock()
+
+	c.send(t, "replay", numbered[0].Seq)
+	for _, want := range numbered[1:] {
+		if _, ok := c.next(func(r game.WSResponse) bool { return r.Seq == want.Seq && r.Type == want.Type }); !ok {
+			t.Fatalf("%s with seq %d wasn't replayed", want.Type, want.Seq)
+		}
+	}
+}
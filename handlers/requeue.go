@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"log"
+	"time"
+)
+
+const (
+	MessageQueued      = "queued"
+	MessageQueueUpdate = "queue_update"
+)
+
+// handleRequeue takes a player out of their finished room and into the matchmaking
+// queue. As soon as a full table is waiting, its players are seated in a new room.
+func handleRequeue(player *game.Player, room *game.Room) {
+	if over, _ := room.Game.IsMatchOver(); !over {
+		log.Printf("Player %s asked for a new table before the match ended", player.ID)
+		return
+	}
+
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
+	room.RemovePlayer(player)
+	cancelRematch(room, player.Name+" left for a new table.")
+	if len(room.Players) == 0 && len(room.SavedPlayers) == 0 {
+		delete(game.Manager.Rooms, room.ID)
+	} else {
+		broadcastRosterUpdate(room)
+	}
+
+	player.Hand = []game.Card{}
+	player.Ready = false
+
+	settings := game.DefaultRoomSettings()
+	table := game.Manager.EnqueueLocked(player, settings.Seats())
+	if table == nil {
+		player.Send(game.WSResponse{
+			Type: MessageQueued,
+			Payload: map[string]interface{}{
+				"position": game.Manager.QueuePositionLocked(player.ID),
+				"needed":   settings.Seats(),
+			},
+		})
+		return
+	}
+
+	seatQueuedTable(table, settings)
+}
+
+// seatQueuedTable opens a new room for players taken off the queue, seating them in
+// the order they queued. The caller must hold game.Manager.Mu.
+func seatQueuedTable(table []*game.Player, settings game.RoomSettings) {
+	room := game.NewRoom(settings)
+	game.Manager.Rooms[room.ID] = room
+
+	for seat, p := range table {
+		p.Index = seat
+		p.Team = game.TeamForSeat(seat)
+		room.Players = append(room.Players, p)
+		room.Track(p)
+		room.Game.Seat(p)
+	}
+	room.UpdateBelowFull(time.Now())
+	log.Printf("Seated %d queued players in new room %s", len(table), room.ID)
+
+	for _, p := range room.Players {
+		sendJoinMessage(p, room)
+	}
+	broadcastRosterUpdate(room)
+	broadcastWaitingForReady(room)
+	scheduleReadyTimeout(room)
+}
+
+// leaveQueueLocked drops a queued player who disconnected and reports whether they
+// were queued. The caller must hold game.Manager.Mu.
+func leaveQueueLocked(player *game.Player) bool {
+	if !game.Manager.DequeueLocked(player.ID) {
+		return false
+	}
+	log.Printf("Player %s left the matchmaking queue", player.ID)
+	for _, p := range game.Manager.Queue {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageQueueUpdate,
+				Payload: map[string]interface{}{
+					"position": game.Manager.QueuePositionLocked(p.ID),
+				},
+			})
+		}
+	}
+	return true
+}
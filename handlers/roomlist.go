@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListRooms returns what a client needs to pick a room to watch; unlike AdminListRooms
+// it never includes hands or other hidden state
+func ListRooms(c *gin.Context) {
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+
+	rooms := make([]gin.H, 0, len(game.Manager.Rooms))
+	for _, room := range game.Manager.Rooms {
+		over, _ := room.Game.IsMatchOver()
+		rooms = append(rooms, gin.H{
+			"id":         room.ID,
+			"seats":      room.Settings.Seats(),
+			"players":    len(room.Players),
+			"spectators": len(room.Observers),
+			"phase":      room.Game.Phase,
+			"round":      room.Game.CurrentRound,
+			"running":    room.Started && !over,
+			"created_at": room.CreatedAt,
+		})
+	}
+	// Oldest first, so the list doesn't reshuffle between requests
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i]["created_at"].(time.Time).Before(rooms[j]["created_at"].(time.Time))
+	})
+
+	c.JSON(http.StatusOK, gin.H{"rooms": rooms})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"log"
+	"time"
+)
+
+const MessageRoomDissolved = "room_dissolved"
+
+// RoomSweepInterval is how often rooms are checked for being idle
+const RoomSweepInterval = time.Minute
+
+// StartRoomSweeper periodically dissolves rooms that stayed short of players for
+// ROOM_IDLE_TIMEOUT (10 minutes by default), and expires saved seats nobody
+// took over within SAVED_PLAYER_TTL (5 minutes by default)
+func StartRoomSweeper() {
+	idleTimeout := config.GetEnvDuration("ROOM_IDLE_TIMEOUT", 10*time.Minute)
+	savedPlayerTTL := config.GetEnvDuration("SAVED_PLAYER_TTL", 5*time.Minute)
+
+	goSafe("room sweeper", func() {
+		ticker := time.NewTicker(RoomSweepInterval)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			for _, room := range removeIdleRooms(now, idleTimeout) {
+				dissolveRoom(room, "Not enough players joined in time.")
+			}
+			for _, room := range removeExpiredSavedPlayers(now, savedPlayerTTL) {
+				dissolveRoom(room, "Nobody took over the empty seat in time.")
+			}
+		}
+	})
+}
+
+// removeIdleRooms takes every room that has been waiting for players longer than idleTimeout
+// out of the manager. The wait counts from when the room last had a free seat, not from its creation.
+func removeIdleRooms(now time.Time, idleTimeout time.Duration) []*game.Room {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
+	var idle []*game.Room
+	for id, room := range game.Manager.Rooms {
+		if !room.Started && len(room.Players) < room.Settings.Seats() && now.Sub(room.BelowFullSince) > idleTimeout {
+			delete(game.Manager.Rooms, id)
+			idle = append(idle, room)
+		}
+	}
+	return idle
+}
+
+// removeExpiredSavedPlayers drops saved seats older than ttl. A paused room left
+// without any saved seat can never resume, so it's taken out of the manager.
+func removeExpiredSavedPlayers(now time.Time, ttl time.Duration) []*game.Room {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
+	var abandoned []*game.Room
+	for id, room := range game.Manager.Rooms {
+		if len(room.SavedPlayers) == 0 {
+			continue
+		}
+
+		for playerID, data := range room.SavedPlayers {
+			if now.Sub(data.SavedAt) > ttl {
+				log.Printf("Saved seat of %s in room %s expired", playerID, room.ID)
+				delete(room.SavedPlayers, playerID)
+			}
+		}
+
+		if len(room.SavedPlayers) == 0 {
+			delete(game.Manager.Rooms, id)
+			abandoned = append(abandoned, room)
+		}
+	}
+	return abandoned
+}
+
+// dissolveRoom tells the remaining players their room is gone and closes their connections
+func dissolveRoom(room *game.Room, reason string) {
+	log.Printf("Dissolving room %s with %d players: %s", room.ID, len(room.Players), reason)
+	closeRoom(room, MessageRoomDissolved, reason, CloseIdle)
+}
+
+// closeRoom stops any countdown or dealing, sends messageType with the reason to every connected
+// player and closes their connections with the close code. The room must already be out of the manager.
+func closeRoom(room *game.Room, messageType, reason string, code int) {
+	cancelDealing(room)
+	cancelTrickAdvance(room)
+	cancelTrumpTimeout(room)
+	cancelTurnTimer(room)
+	if room.CancelStart != nil {
+		room.CancelStart()
+	}
+
+	for _, p := range room.Players {
+		if !p.Connected {
+			continue
+		}
+		p.Send(game.WSResponse{
+			Type: messageType,
+			Payload: map[string]interface{}{
+				"room_id": room.ID,
+				"message": reason,
+			},
+		})
+		closeConn(p, code, messageType)
+	}
+
+	for _, o := range room.Observers {
+		o.Conn.WriteJSON(game.WSResponse{
+			Type: messageType,
+			Payload: map[string]interface{}{
+				"room_id": room.ID,
+				"message": reason,
+			},
+		})
+		closeSocket(o.Conn, code, messageType)
+	}
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+func TestIdleRoomsAreTimedFromTheLastFreeSeat(t *testing.T) {
+	fastGame(t)
+	const idleTimeout = 10 * time.Minute
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	room := game.NewRoom(game.RoomSettings{TableSize: 4})
+	room.CreatedAt, room.BelowFullSince = start, start
+	seat := func(i int, now time.Time) {
+		room.Players = append(room.Players, &game.Player{ID: fmt.Sprint("p", i), Index: i})
+		room.UpdateBelowFull(now)
+	}
+	for i := 0; i < 3; i++ {
+		seat(i, start)
+	}
+	game.Manager.Rooms[room.ID] = room
+
+	if idle := removeIdleRooms(at(9*time.Minute), idleTimeout); len(idle) != 0 {
+		t.Fatal("room dissolved before the idle timeout")
+	}
+
+	// The table fills, then someone leaves and the wait starts over
+	seat(3, at(5*time.Minute))
+	if !room.BelowFullSince.IsZero() {
+		t.Fatalf("BelowFullSince = %v for a full room, want zero", room.BelowFullSince)
+	}
+	if idle := removeIdleRooms(at(30*time.Minute), idleTimeout); len(idle) != 0 {
+		t.Fatal("full room dissolved")
+	}
+	room.Players = room.Players[:3]
+	room.UpdateBelowFull(at(31 * time.Minute))
+
+	if idle := removeIdleRooms(at(40*time.Minute), idleTimeout); len(idle) != 0 {
+		t.Fatal("room dissolved 9 minutes after it lost a player, timed from its creation")
+	}
+	idle := removeIdleRooms(at(41*time.Minute+time.Second), idleTimeout)
+	if len(idle) != 1 || idle[0] != room {
+		t.Fatalf("removeIdleRooms() = %v, want the room that stayed short past the timeout", idle)
+	}
+	if _, ok := game.Manager.Rooms[room.ID]; ok {
+		t.Fatal("dissolved room is still in the manager")
+	}
+}
+
+func TestSecondJoinerIsAnnouncedToTheFirst(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	first := dial(t, srv, "")
+	firstID := first.expect(t, "join_room")["your_id"].(string)
+	second := dial(t, srv, "")
+	secondID := second.expect(t, "join_room")["your_id"].(string)
+
+	for {
+		var roster struct {
+			Players []struct {
+				ID        string `json:"id"`
+				Team      string `json:"team"`
+				Index     int    `json:"index"`
+				Connected bool   `json:"connected"`
+			} `json:"players"`
+		}
+		decode(t, first.expect(t, MessageRosterUpdate), &roster)
+		if len(roster.Players) < 2 {
+			continue
+		}
+		for i, want := range []string{firstID, secondID} {
+			p := roster.Players[i]
+			if p.ID != want || p.Index != i || p.Team != game.TeamForSeat(i) || !p.Connected {
+				t.Fatalf("roster_update = %+v, want %s and %s seated in order", roster.Players, firstID, secondID)
+			}
+		}
+		return
+	}
+}
+
+func TestExpiredSavedPlayersArePurged(t *testing.T) {
+	fastGame(t)
+	const ttl = 5 * time.Minute
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	room := game.NewRoom(game.RoomSettings{TableSize: 4})
+	room.Started = true
+	room.Players = []*game.Player{{ID: "p0", Index: 0}, {ID: "p1", Index: 1}}
+	room.SavedPlayers = map[string]*game.SavedPlayerData{
+		"p2": {PlayerID: "p2", Index: 2, SavedAt: start},
+		"p3": {PlayerID: "p3", Index: 3, SavedAt: start.Add(3 * time.Minute)},
+	}
+	room.Game.Pause()
+	game.Manager.Rooms[room.ID] = room
+
+	if gone := removeExpiredSavedPlayers(start.Add(6*time.Minute), ttl); len(gone) != 0 {
+		t.Fatal("room dissolved while a saved seat was still fresh")
+	}
+	if _, ok := room.SavedPlayers["p2"]; ok || len(room.SavedPlayers) != 1 {
+		t.Fatalf("saved seats after the first expiry: %v, want only p3", room.SavedPlayers)
+	}
+
+	gone := removeExpiredSavedPlayers(start.Add(9*time.Minute), ttl)
+	if len(gone) != 1 || gone[0] != room {
+		t.Fatalf("removeExpiredSavedPlayers() = %v, want the room without saved seats", gone)
+	}
+	if _, ok := game.Manager.Rooms[room.ID]; ok {
+		t.Fatal("the unrecoverable room is still in the manager")
+	}
+
+	// New players aren't routed into the paused room anymore
+	if next := getAvailableRoom(); next == room {
+		t.Fatal("getAvailableRoom() returned the purged room")
+	}
+}
+
+func TestNewConnectionTakesTheSavedSeatOfAPausedRoom(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+	tb.clients[1].send(t, "leave_game", nil)
+	waitFor(t, "the game to pause", tb.room.Game.Halted)
+
+	replacement := dial(t, srv, "")
+	replaced := replacement.expect(t, MessagePlayerReplaced)
+	if replaced["new_player_id"] != tb.ids[1] {
+		t.Fatalf("player_replaced = %v, want the seat of %s", replaced, tb.ids[1])
+	}
+	waitFor(t, "the game to resume", func() bool { return !tb.room.Game.Halted() })
+
+	// With the seat filled, the next newcomer gets a room of their own
+	newcomer := dial(t, srv, "")
+	if roomID := newcomer.expect(t, "join_room")["room_id"]; roomID == tb.room.ID {
+		t.Fatalf("a newcomer joined the full room %s", roomID)
+	}
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	if len(tb.room.Players) != 4 || len(tb.room.SavedPlayers) != 0 || len(game.Manager.Rooms) != 2 {
+		t.Fatalf("%d players and %d saved seats in the room with %d rooms, want 4, 0 and 2",
+			len(tb.room.Players), len(tb.room.SavedPlayers), len(game.Manager.Rooms))
+	}
+}
+
+func TestTakingASavedSeatDropsPlayedCardsFromTheHand(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+	trick := tb.playFirstTrick(t)
+	tb.clients[1].send(t, "leave_game", nil)
+	waitFor(t, "the game to pause", tb.room.Game.Halted)
+
+	// A card played in the first trick turns up in the saved hand
+	game.Manager.Mu.Lock()
+	saved := tb.room.SavedPlayers[tb.ids[1]]
+	want := append([]game.Card(nil), saved.Hand...)
+	saved.Hand = append(saved.Hand, trick.Cards[0])
+	game.Manager.Mu.Unlock()
+
+	replacement := dial(t, srv, "")
+	replacement.expect(t, MessagePlayerReplaced)
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	for _, p := range tb.room.Players {
+		if p.ID == tb.ids[1] && !reflect.DeepEqual(p.Hand, want) {
+			t.Fatalf("restored hand %v, want %v without the played %v", p.Hand, want, trick.Cards[0])
+		}
+	}
+}
+
+func TestLeavingMidTrickUnderDiscardReturnsTheCards(t *testing.T) {
+	fastGame(t)
+	t.Setenv("TRICK_ON_LEAVE", game.TrickDiscard)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+	tb.play(t, tb.firstLegal)
+	tb.play(t, tb.firstLegal)
+
+	game.Manager.Mu.RLock()
+	leader, second := tb.room.Game.TrickPlayOrder[0].ID, tb.room.Game.TrickPlayOrder[1].ID
+	trick := append([]game.Card(nil), tb.room.Game.CurrentTrick...)
+	game.Manager.Mu.RUnlock()
+
+	// The second player leaves with their card still on the table
+	tb.client(t, second).send(t, "leave_game", nil)
+	waitFor(t, "the game to pause", tb.room.Game.Halted)
+
+	var synced struct {
+		Hand []game.Card `json:"hand"`
+	}
+	decode(t, tb.client(t, leader).expect(t, MessageHandSync), &synced)
+	if len(synced.Hand) != 13 {
+		t.Fatalf("hand_sync of the leader has %d cards, want 13", len(synced.Hand))
+	}
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	g := tb.room.Game
+	if len(g.CurrentTrick) != 0 || g.CurrentPlayerID != leader {
+		t.Fatalf("trick %v with %s to play after the leave, want it cleared for %s to lead again", g.CurrentTrick, g.CurrentPlayerID, leader)
+	}
+	hands := map[string][]game.Card{second: tb.room.SavedPlayers[second].Hand}
+	for _, p := range g.Players {
+		if p.ID == leader {
+			hands[leader] = p.Hand
+		}
+	}
+	for i, id := range []string{leader, second} {
+		if !containsCard(hands[id], trick[i]) || len(hands[id]) != 13 {
+			t.Errorf("%s holds %v, want 13 cards with %v back", id, hands[id], trick[i])
+		}
+	}
+}
+
+// containsCard reports whether the hand holds the card
+func containsCard(hand []game.Card, c game.Card) bool {
+	for _, h := range hand {
+		if h == c {
+			return true
+		}
+	}
+	return false
+}
+
+func TestANewPlayerIsntSeatedInAnAbandonedGame(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	// Everyone was removed from a room after its match ended
+	stale := game.NewRoom(game.DefaultRoomSettings())
+	stale.Started = true
+	stale.Game.Phase = game.PhaseMatchOver
+	game.Manager.Mu.Lock()
+	game.Manager.Rooms[stale.ID] = stale
+	game.Manager.Mu.Unlock()
+
+	join := dial(t, srv, "").expect(t, "join_room")
+	if join["room_id"] == stale.ID {
+		t.Fatal("the new player was seated in the abandoned room")
+	}
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	if _, ok := game.Manager.Rooms[stale.ID]; ok {
+		t.Error("the abandoned room is still open")
+	}
+	room := game.Manager.Rooms[join["room_id"].(string)]
+	if room == nil {
+		t.Fatalf("join_room = %v names no open room", join)
+	}
+	if over, _ := room.Game.IsMatchOver(); over || room.Started || room.Game.Phase != game.PhaseLobby || len(room.Players) != 1 {
+		t.Fatalf("the new player landed in a room with started %v, phase %s and %d players, want a fresh lobby",
+			room.Started, room.Game.Phase, len(room.Players))
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+)
+
+func TestScorePayloadShape(t *testing.T) {
+	room := game.NewRoom(game.DefaultRoomSettings())
+	room.Game.Scores = map[string]int{"team1": 3, "team2": 1}
+	room.Game.RoundScores = map[string]int{"team1": 2}
+
+	payloads := map[string]map[string]interface{}{
+		"withScores":  withScores(map[string]interface{}{}, room),
+		"game_update": maskGameStateFor("", room),
+	}
+	for name, payload := range payloads {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[string]interface{}{
+			"tricks":              map[string]interface{}{"team1": 3.0, "team2": 1.0},
+			"rounds":              map[string]interface{}{"team1": 2.0},
+			"tricks_to_win_round": 7.0,
+			"rounds_to_win_match": float64(game.TargetScore),
+		}
+		for key, value := range want {
+			if !reflect.DeepEqual(got[key], value) {
+				t.Errorf("%s: %s = %v, want %v", name, key, got[key], value)
+			}
+		}
+		for _, old := range []string{"scores", "round_scores"} {
+			if _, ok := got[old]; ok {
+				t.Errorf("%s still has %q", name, old)
+			}
+		}
+	}
+}
+
+func TestPlayerTricksAddUpToTheTeamTricks(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+	teamOf := make(map[string]string)
+	game.Manager.Mu.RLock()
+	for _, p := range tb.room.Players {
+		teamOf[p.ID] = p.Team
+	}
+	game.Manager.Mu.RUnlock()
+
+	for tb.playing() {
+		tb.play(t, tb.firstLegal)
+	}
+	var result struct {
+		Tricks    map[string]int `json:"tricks"`
+		TricksWon map[string]int `json:"tricks_won"`
+	}
+	decode(t, tb.clients[0].expect(t, "round_winner"), &result)
+
+	sums := make(map[string]int)
+	for id, n := range result.TricksWon {
+		sums[teamOf[id]] += n
+	}
+	for _, team := range []string{"team1", "team2"} {
+		if sums[team] != result.Tricks[team] {
+			t.Fatalf("player tricks %v add up to %v, want the team tricks %v", result.TricksWon, sums, result.Tricks)
+		}
+	}
+	tb.awaitSecondRound(t)
+}
+
+func TestCustomKotScoresASevenNilRound(t *testing.T) {
+	fastGame(t)
+	t.Setenv("SCORE_KOT", "4")
+	t.Setenv("GAME_START_COUNTDOWN", "1s")
+	srv := newTestServer(t)
+
+	hands := wholeSuits(t)
+	utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+		return stackDeck(hands, settings.DealBatches())
+	}
+
+	tb := joinTable(t, srv, 4)
+	playKotRound(t, tb)
+
+	starting := tb.clients[1].expect(t, MessageGameStarting)
+	if rules, _ := starting["scoring_rules"].(map[string]interface{}); rules["kot"] != 4.0 {
+		t.Fatalf("game_starting = %v, want the Kot worth 4", starting)
+	}
+	var result struct {
+		Winner        string         `json:"winner"`
+		TrumpTeam     string         `json:"trump_team"`
+		PointsAwarded int            `json:"points_awarded"`
+		Tricks        map[string]int `json:"tricks"`
+		Rounds        map[string]int `json:"rounds"`
+	}
+	decode(t, tb.clients[1].expect(t, "round_winner"), &result)
+	if result.Winner != result.TrumpTeam || result.Tricks[result.Winner] != 7 {
+		t.Fatalf("round_winner = %+v, want the Trump team to win 7-0", result)
+	}
+	if result.PointsAwarded != 4 || result.Rounds[result.Winner] != 4 {
+		t.Fatalf("round_winner = %+v, want the Kot worth 4", result)
+	}
+	tb.awaitSecondRound(t)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Session tells an authenticated client whether its account is seated in a room,
+// so a reloaded client knows it should reconnect
+func Session(c *gin.Context) {
+	room, player := game.Manager.FindRoomByUserID(c.GetString("user_id"))
+	if room == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room_id":    room.ID,
+		"seat_index": player.Index,
+		"team":       player.Team,
+		"connected":  player.Connected,
+		"paused":     room.Game.Phase == game.PhasePaused && len(room.SavedPlayers) > 0,
+	})
+}
+
+// Stats returns the authenticated user's totals over their finished matches
+func Stats(c *gin.Context) {
+	db, cancel := requestDB(c)
+	defer cancel()
+
+	stats, err := models.UserStats(db, c.GetString("user_id"))
+	if err != nil {
+		respondDBError(c, err, http.StatusInternalServerError, utils.CodeInternal, "Failed to load stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
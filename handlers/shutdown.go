@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const MessageServerShutdown = "server_shutdown"
+
+// ShutdownDrainWindow gives clients a moment to handle the shutdown notice before their
+// connection closes. Tests shorten it.
+var ShutdownDrainWindow = 3 * time.Second
+
+// NotifyShutdown tells every connection the server is going down: the players of
+// every room, their spectators and the players waiting in the queue. It waits for
+// the drain window and then closes the connections.
+func NotifyShutdown() {
+	game.Manager.Mu.RLock()
+	var conns []*game.Conn
+	for _, room := range game.Manager.Rooms {
+		for _, p := range room.Players {
+			if p.Connected && p.Conn != nil {
+				conns = append(conns, p.Conn)
+			}
+		}
+		for _, o := range room.Observers {
+			conns = append(conns, o.Conn)
+		}
+	}
+	for _, p := range game.Manager.Queue {
+		if p.Connected && p.Conn != nil {
+			conns = append(conns, p.Conn)
+		}
+	}
+	game.Manager.Mu.RUnlock()
+
+	log.Printf("Notifying %d connections about shutdown", len(conns))
+	for _, conn := range conns {
+		conn.WriteJSON(game.WSResponse{
+			Type: MessageServerShutdown,
+			Payload: map[string]interface{}{
+				"message": "Server is shutting down.",
+			},
+		})
+	}
+
+	time.Sleep(ShutdownDrainWindow)
+
+	for _, conn := range conns {
+		closeSocket(conn, websocket.CloseGoingAway, MessageServerShutdown)
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestShutdownNotifiesPlayersSpectatorsAndTheQueue(t *testing.T) {
+	fastGame(t)
+	defer func(d time.Duration) { ShutdownDrainWindow = d }(ShutdownDrainWindow)
+	ShutdownDrainWindow = 0
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	watcher := dial(t, srv, "watch="+tb.room.ID)
+	watcher.expect(t, MessageObserverJoined)
+
+	// Once the match is over seat 0 asks for a new table and waits in the queue
+	forfeitGame(tb.room, "team1")
+	queued := tb.clients[0]
+	queued.send(t, "requeue", nil)
+	queued.expect(t, MessageQueued)
+
+	NotifyShutdown()
+
+	for name, c := range map[string]*testClient{"seated": tb.clients[1], "spectator": watcher, "queued": queued} {
+		c.expect(t, MessageServerShutdown)
+		if code := c.closeCode(t); code != websocket.CloseGoingAway {
+			t.Errorf("%s connection closed with %d, want %d", name, code, websocket.CloseGoingAway)
+		}
+	}
+}
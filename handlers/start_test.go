@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+func TestAbortedStartTellsThePlayersWhy(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	game.Manager.Mu.Lock()
+	tb.room.Players[3].Index = tb.room.Players[2].Index
+	game.Manager.Mu.Unlock()
+
+	for _, c := range tb.clients {
+		c.send(t, "ready", nil)
+	}
+	for _, c := range tb.clients {
+		cancelled := c.expect(t, MessageGameStartCancelled)
+		if msg, _ := cancelled["message"].(string); !strings.Contains(msg, "seat") {
+			t.Fatalf("game_start_cancelled = %v, want the shared seat as the reason", cancelled)
+		}
+		c.expect(t, MessageRosterUpdate)
+	}
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	if tb.room.Started || tb.room.Game.Phase != game.PhaseLobby {
+		t.Fatalf("room is started=%t in phase %s, want it back in the lobby", tb.room.Started, tb.room.Game.Phase)
+	}
+}
+
+func TestDealingWaitsForTheLastReady(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	last := tb.clients[3]
+	for _, c := range tb.clients[:3] {
+		c.send(t, "ready", nil)
+	}
+	_, ok := last.next(func(r game.WSResponse) bool {
+		notReady, _ := payloadOf(r)["not_ready"].([]interface{})
+		return r.Type == MessageWaitingForReady && len(notReady) == 1 && notReady[0] == tb.ids[3]
+	})
+	if !ok {
+		t.Fatalf("no waiting_for_ready naming only %s; got %v", tb.ids[3], last.types())
+	}
+
+	game.Manager.Mu.RLock()
+	started, phase := tb.room.Started, tb.room.Game.Phase
+	game.Manager.Mu.RUnlock()
+	if started || phase != game.PhaseLobby || last.received("round_start") {
+		t.Fatalf("room is started=%t in phase %s before the last ready, want nothing dealt", started, phase)
+	}
+
+	last.send(t, "ready", nil)
+	last.expect(t, "round_start")
+	waitFor(t, "the trump prompt", func() bool { return tb.room.Game.Phase == game.PhaseWaitingTrump })
+}
+
+func TestLeavingDuringTheCountdownAbortsTheStart(t *testing.T) {
+	fastGame(t)
+	t.Setenv("GAME_START_COUNTDOWN", "1s")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	for _, c := range tb.clients {
+		c.send(t, "ready", nil)
+	}
+	tb.clients[0].expect(t, MessageGameStarting)
+	tb.clients[2].send(t, "leave_game", nil)
+
+	for _, seat := range []int{0, 1, 3} {
+		tb.clients[seat].expect(t, MessageGameStartCancelled)
+	}
+
+	// The deal doesn't begin once the countdown would have run out
+	time.Sleep(1500 * time.Millisecond)
+	game.Manager.Mu.RLock()
+	started, dealt := tb.room.Started, tb.room.Game.TrumpPlayer != nil
+	game.Manager.Mu.RUnlock()
+	if started || dealt || tb.clients[0].received("round_start") {
+		t.Fatalf("room is started=%t with a Trump Player=%t after the cancelled countdown, want it waiting", started, dealt)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"hokm-backend/utils"
+	"log"
+)
+
+const MessageSwapRequested = "swap_requested"
+
+// handleSwapSeat lets two players in a lobby trade seats, and so teams, to pick their
+// partner. The first swap_seat asks; the swap happens once the other player sends
+// swap_seat naming the first.
+func handleSwapSeat(player *game.Player, room *game.Room, msg game.WSMessage) {
+	var targetID string
+	if !decodeData(player, msg, &targetID) {
+		return
+	}
+
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
+	if room.Started {
+		sendError(player.Conn, utils.CodeWrongPhase, "Seats can only be swapped before the game starts")
+		return
+	}
+
+	var target *game.Player
+	for _, p := range room.Players {
+		if p.ID == targetID && p.ID != player.ID {
+			target = p
+		}
+	}
+	if target == nil {
+		sendError(player.Conn, utils.CodeInvalidRequest, "No such player to swap seats with")
+		return
+	}
+
+	// The other player already asked for this swap: agreed
+	if room.SwapRequests[target.ID] == player.ID {
+		room.DropSwapRequests(player.ID)
+		room.DropSwapRequests(target.ID)
+		room.SwapSeats(player, target)
+		player.Ready = false
+		target.Ready = false
+
+		log.Printf("Players %s and %s swapped seats in room %s", player.ID, target.ID, room.ID)
+		broadcastRosterUpdate(room)
+		return
+	}
+
+	if room.SwapRequests == nil {
+		room.SwapRequests = make(map[string]string)
+	}
+	room.SwapRequests[player.ID] = target.ID
+
+	for _, p := range []*game.Player{player, target} {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageSwapRequested,
+				Payload: map[string]interface{}{
+					"from_id": player.ID,
+					"to_id":   target.ID,
+				},
+			})
+		}
+	}
+}
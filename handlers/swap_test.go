@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+)
+
+func TestSwapSeatTradesSeatsAndTeamsOnceBothAgree(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	seatOf := func(id string) (int, string) {
+		game.Manager.Mu.RLock()
+		defer game.Manager.Mu.RUnlock()
+		for _, p := range tb.room.Players {
+			if p.ID == id {
+				return p.Index, p.Team
+			}
+		}
+		t.Fatalf("%s isn't seated", id)
+		return 0, ""
+	}
+	a, b := tb.ids[0], tb.ids[1]
+	seatA, teamA := seatOf(a)
+	seatB, teamB := seatOf(b)
+	if teamA == teamB {
+		t.Fatalf("%s and %s start on the same team", a, b)
+	}
+
+	// Asking alone changes nothing
+	tb.client(t, a).send(t, "swap_seat", b)
+	if req := tb.client(t, b).expect(t, MessageSwapRequested); req["from_id"] != a || req["to_id"] != b {
+		t.Fatalf("swap_requested = %v, want %s asking %s", req, a, b)
+	}
+	if seat, _ := seatOf(a); seat != seatA {
+		t.Fatalf("%s moved to seat %d before %s agreed", a, seat, b)
+	}
+
+	tb.client(t, b).send(t, "swap_seat", a)
+	for {
+		var roster struct {
+			Players []struct {
+				ID    string `json:"id"`
+				Team  string `json:"team"`
+				Index int    `json:"index"`
+			} `json:"players"`
+		}
+		decode(t, tb.clients[2].expect(t, MessageRosterUpdate), &roster)
+		swapped := false
+		for _, p := range roster.Players {
+			if p.ID == a && p.Index == seatB && p.Team == teamB {
+				swapped = true
+			}
+		}
+		if swapped {
+			break
+		}
+	}
+	if seat, team := seatOf(a); seat != seatB || team != teamB {
+		t.Errorf("%s is in seat %d on %s, want seat %d on %s", a, seat, team, seatB, teamB)
+	}
+	if seat, team := seatOf(b); seat != seatA || team != teamA {
+		t.Errorf("%s is in seat %d on %s, want seat %d on %s", b, seat, team, seatA, teamA)
+	}
+	game.Manager.Mu.RLock()
+	for i, p := range tb.room.Game.Players {
+		if p.Index != i {
+			t.Errorf("game seat %d holds %s of seat %d", i, p.ID, p.Index)
+		}
+	}
+	game.Manager.Mu.RUnlock()
+}
+
+func TestSwapSeatIsRefusedOnceTheGameStarts(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+
+	c := tb.client(t, tb.ids[0])
+	c.send(t, "swap_seat", tb.ids[1])
+	var got utils.APIError
+	decode(t, c.expect(t, MessageError), &got)
+	if got.Code != utils.CodeWrongPhase {
+		t.Fatalf("swap_seat during the game got %+v, want %s", got, utils.CodeWrongPhase)
+	}
+}
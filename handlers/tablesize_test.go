@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestSixPlayerTableDealsTheDeckWithoutTwos(t *testing.T) {
+	fastGame(t)
+	t.Setenv("TABLE_SIZE", "6")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 6)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	seen := make(map[game.Card]bool)
+	for _, p := range tb.room.Game.Players {
+		if len(p.Hand) != 8 {
+			t.Errorf("seat %d holds %d cards, want 8", p.Index, len(p.Hand))
+		}
+		for _, c := range p.Hand {
+			if c.Rank == "2" {
+				t.Errorf("seat %d was dealt %v, 2s are out of a 6-player deck", p.Index, c)
+			}
+			if seen[c] {
+				t.Errorf("%v was dealt twice", c)
+			}
+			seen[c] = true
+		}
+	}
+	if len(seen) != 48 {
+		t.Errorf("%d distinct cards dealt, want 48", len(seen))
+	}
+}
+
+func TestSixPlayerTableEndsATiedRoundAgainstTheTrumpTeam(t *testing.T) {
+	fastGame(t)
+	t.Setenv("TABLE_SIZE", "6")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 6)
+	tb.startGame(t)
+	tb.chooseTrump(t, "spades")
+
+	// Rig the hands so the teams take turns winning: trick 2k goes to seat 1 (team1)
+	// and trick 2k+1 to seat 0 (team2, the Trump team), so the Round ends 4-4.
+	// Every trick is one suit, ranked 3-8 or 9-A, and its winner holds the top card.
+	suits := []string{"hearts", "diamonds", "clubs", "spades"}
+	rankGroups := [][]string{{"3", "4", "5", "6", "7", "8"}, {"9", "10", "J", "Q", "K", "A"}}
+	planned := make(map[int][]game.Card) // Seat -> card for each trick
+	for trick := 0; trick < 8; trick++ {
+		winner := 1 - trick%2
+		for seat := 0; seat < 6; seat++ {
+			rank := rankGroups[trick%2][(seat-winner+5)%6]
+			planned[seat] = append(planned[seat], card(t, suits[trick/2], rank))
+		}
+	}
+	game.Manager.Mu.Lock()
+	for _, p := range tb.room.Game.Players {
+		p.Hand = append([]game.Card(nil), planned[p.Index]...)
+	}
+	game.Manager.Mu.Unlock()
+
+	for trick := 0; trick < 8; trick++ {
+		for i := 0; i < 6; i++ {
+			tb.play(t, func(p *game.Player) game.Card { return planned[p.Index][trick] })
+		}
+	}
+
+	result := tb.clients[0].expect(t, "round_winner")
+	if result["winner"] != "team1" {
+		t.Fatalf("round_winner = %v, want team1 for a 4-4 Round the Trump team tied", result)
+	}
+	tb.awaitSecondRound(t)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const MessageTeamChat = "team_chat"
+
+// MaxChatLength caps a chat message, in characters
+const MaxChatLength = 200
+
+// sanitizeChat trims the message, drops control characters and cuts it to
+// MaxChatLength. An empty result is reported as false.
+func sanitizeChat(text string) (string, bool) {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	text = strings.TrimSpace(text)
+	if runes := []rune(text); len(runes) > MaxChatLength {
+		text = string(runes[:MaxChatLength])
+	}
+	return text, text != ""
+}
+
+// ChatCooldown is the minimum time between two chat messages from the same player
+const ChatCooldown = time.Second
+
+// Chat can be switched off on its own with CHAT_ENABLED=false
+func chatEnabled() bool {
+	return config.GetEnv("CHAT_ENABLED", "true") != "false"
+}
+
+// handleTeamChat sends a message to the player's teammates only; spectators and
+// opponents never see it
+func handleTeamChat(player *game.Player, room *game.Room, msg game.WSMessage) {
+	if !chatEnabled() {
+		log.Println("Chat is disabled")
+		return
+	}
+
+	var text string
+	if !decodeData(player, msg, &text) {
+		return
+	}
+	text, ok := sanitizeChat(text)
+	if !ok {
+		log.Printf("Rejected empty team chat from %s", player.ID)
+		return
+	}
+
+	game.Manager.Mu.Lock()
+	if time.Since(player.LastChatAt) < ChatCooldown {
+		game.Manager.Mu.Unlock()
+		log.Printf("Team chat from %s dropped by rate limit", player.ID)
+		return
+	}
+	player.LastChatAt = time.Now()
+	var teammates []*game.Player
+	for _, p := range room.Players {
+		if p.Connected && p.Team == player.Team {
+			teammates = append(teammates, p)
+		}
+	}
+	game.Manager.Mu.Unlock()
+
+	for _, p := range teammates {
+		p.Send(game.WSResponse{
+			Type: MessageTeamChat,
+			Payload: map[string]interface{}{
+				"player_id": player.ID,
+				"message":   text,
+				"scope":     "team",
+			},
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+// teams splits the table's clients into the sender's team and the opponents
+func (tb *table) teams(t *testing.T, senderID string) (partners, opponents []*testClient) {
+	t.Helper()
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	var team string
+	for _, p := range tb.room.Players {
+		if p.ID == senderID {
+			team = p.Team
+		}
+	}
+	for _, p := range tb.room.Players {
+		switch {
+		case p.ID == senderID:
+		case p.Team == team:
+			partners = append(partners, tb.client(t, p.ID))
+		default:
+			opponents = append(opponents, tb.client(t, p.ID))
+		}
+	}
+	return partners, opponents
+}
+
+// expectReadyFrom waits for the waiting_for_ready that follows the first ready of the table
+func expectReadyFrom(t *testing.T, c *testClient, seats int) {
+	t.Helper()
+	_, ok := c.next(func(r game.WSResponse) bool {
+		notReady, _ := payloadOf(r)["not_ready"].([]interface{})
+		return r.Type == MessageWaitingForReady && len(notReady) == seats-1
+	})
+	if !ok {
+		t.Fatalf("no waiting_for_ready after the ready; got %v", c.types())
+	}
+}
+
+func TestTeamChatReachesOnlyTheTeammate(t *testing.T) {
+	fastGame(t)
+	t.Setenv("REACTIONS_ENABLED", "false")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	sender := tb.clients[0]
+	partners, opponents := tb.teams(t, tb.ids[0])
+	if len(partners) != 1 || len(opponents) != 2 {
+		t.Fatalf("got %d partners and %d opponents", len(partners), len(opponents))
+	}
+
+	// Chat has its own switch, so it works with reactions off
+	sender.send(t, "team_chat", "  lead \x07spades ")
+	got := partners[0].expect(t, MessageTeamChat)
+	if got["player_id"] != tb.ids[0] || got["message"] != "lead spades" || got["scope"] != "team" {
+		t.Fatalf("team_chat = %v", got)
+	}
+	if sender.expect(t, MessageTeamChat)["message"] != "lead spades" {
+		t.Fatal("the sender didn't get their own message")
+	}
+
+	// Every opponent sees the sender's ready, which follows the chat on the wire
+	sender.send(t, "ready", nil)
+	for _, c := range opponents {
+		expectReadyFrom(t, c, 4)
+		if c.received(MessageTeamChat) {
+			t.Fatalf("an opponent received team chat: %v", c.types())
+		}
+	}
+}
+
+func TestTeamChatHasItsOwnRateLimit(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	sender := tb.clients[0]
+	partners, _ := tb.teams(t, tb.ids[0])
+
+	// A reaction right before doesn't use up the chat allowance
+	sender.send(t, "reaction", "nice")
+	partners[0].expect(t, MessagePlayerReaction)
+	sender.send(t, "team_chat", "first")
+	sender.send(t, "team_chat", "too soon")
+	sender.send(t, "ready", nil)
+	expectReadyFrom(t, partners[0], 4)
+
+	var chats []string
+	for _, p := range partners[0].all(MessageTeamChat) {
+		chats = append(chats, p["message"].(string))
+	}
+	if len(chats) != 1 || chats[0] != "first" {
+		t.Fatalf("partner got chats %v, want only the first", chats)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+func TestRejoinersTakeTheTeamOfTheirSeat(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	// Two players of the lobby drop out for good, leaving seats 1 and 2 free
+	tb := joinTable(t, srv, 4)
+	tb.holdSeatsFor(10 * time.Millisecond)
+	for _, seat := range []int{1, 2} {
+		tb.clients[seat].ws.Close()
+	}
+	waitFor(t, "both players to be removed", func() bool { return len(tb.room.Players) == 2 })
+
+	for i := 0; i < 2; i++ {
+		dial(t, srv, "").expect(t, "join_room")
+	}
+	waitFor(t, "the table to fill again", func() bool { return len(tb.room.Players) == 4 })
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	seats := make(map[int]bool)
+	for _, p := range tb.room.Players {
+		if want := game.TeamForSeat(p.Index); p.Team != want {
+			t.Errorf("player %s in seat %d is on %s, want %s", p.ID, p.Index, p.Team, want)
+		}
+		seats[p.Index] = true
+	}
+	if len(seats) != 4 {
+		t.Fatalf("players sit in seats %v, want four different seats", seats)
+	}
+}
+
+func TestTurnSurvivesAReplacementMidTrick(t *testing.T) {
+	fastGame(t)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "clubs")
+	tb.play(t, tb.firstLegal)
+	tb.play(t, tb.firstLegal)
+
+	// The player on turn leaves and a newcomer takes the seat
+	game.Manager.Mu.RLock()
+	leaverID := tb.room.Game.CurrentPlayerID
+	game.Manager.Mu.RUnlock()
+	leaver := tb.client(t, leaverID)
+	leaver.send(t, "leave_game", nil)
+	waitFor(t, "the game to pause", tb.room.Game.Halted)
+	leaver.ws.Close()
+
+	replacement := dial(t, srv, "")
+	if id := replacement.expect(t, MessagePlayerReplaced)["new_player_id"]; id != leaverID {
+		t.Fatalf("the newcomer took the seat of %v, want %s", id, leaverID)
+	}
+	for seat, id := range tb.ids {
+		if id == leaverID {
+			tb.clients[seat] = replacement
+		}
+	}
+	waitFor(t, "the game to resume", func() bool { return !tb.room.Game.Halted() })
+
+	game.Manager.Mu.RLock()
+	current := tb.room.Game.CurrentPlayer()
+	game.Manager.Mu.RUnlock()
+	if current == nil || current.ID != leaverID || current.Conn == nil {
+		t.Fatalf("the turn is with %v after the replacement, want the seat of %s", current, leaverID)
+	}
+	tb.play(t, tb.firstLegal)
+	tb.play(t, tb.firstLegal)
+	waitFor(t, "the trick to complete", func() bool { return len(tb.room.Game.RoundTricks) == 1 })
+}
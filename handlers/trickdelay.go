@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"time"
+)
+
+// DefaultTrickAdvanceDelay is how long a completed trick stays on the table before
+// the cleared state is broadcast, unless TRICK_ADVANCE_DELAY says otherwise
+const DefaultTrickAdvanceDelay = 1500 * time.Millisecond
+
+// scheduleTrickAdvance broadcasts the cleared trick and the next turn once the delay has
+// passed, without holding up the caller. A new card or the game pausing cancels it.
+// The caller must hold game.Manager.Mu.
+func scheduleTrickAdvance(room *game.Room) {
+	delay := config.GetEnvDuration("TRICK_ADVANCE_DELAY", DefaultTrickAdvanceDelay)
+	if delay <= 0 {
+		broadcastGameUpdateLocked(room)
+		broadcastTurnUpdate(room)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelTrickAdvance(room)
+	room.CancelAdvance = cancel
+
+	goSafe("trick advance in room "+room.ID, func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		game.Manager.Mu.Lock()
+		defer game.Manager.Mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		room.CancelAdvance = nil
+
+		broadcastGameUpdateLocked(room)
+		broadcastTurnUpdate(room)
+	})
+}
+
+// cancelTrickAdvance drops a pending post-trick broadcast. The caller must hold game.Manager.Mu.
+func cancelTrickAdvance(room *game.Room) {
+	if room != nil && room.CancelAdvance != nil {
+		room.CancelAdvance()
+		room.CancelAdvance = nil
+	}
+}
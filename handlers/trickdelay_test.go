@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+func TestClearedTrickArrivesAfterTheAdvanceDelay(t *testing.T) {
+	fastGame(t)
+	const delay = 300 * time.Millisecond
+	t.Setenv("TRICK_ADVANCE_DELAY", delay.String())
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	for i := 0; i < 3; i++ {
+		tb.play(t, tb.firstLegal)
+	}
+	start := time.Now()
+	tb.play(t, tb.firstLegal)
+
+	watcher := tb.clients[0]
+	watcher.expect(t, "trick_complete")
+	var update struct {
+		Game struct {
+			CurrentTrick []game.Card `json:"current_trick"`
+		} `json:"game"`
+	}
+	decode(t, watcher.expect(t, "game_update"), &update)
+	if len(update.Game.CurrentTrick) != 0 {
+		t.Fatalf("game_update after trick_complete shows %v, want the cleared trick", update.Game.CurrentTrick)
+	}
+	watcher.expect(t, "turn_update")
+	if took := time.Since(start); took < delay {
+		t.Fatalf("the cleared trick arrived %v after the last card, want at least %v", took, delay)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"log"
+)
+
+const MessageTrumpWarning = "trump_warning"
+
+// What happens when the Trump Player names a suit they don't hold, set by TRUMP_NOT_IN_HAND.
+// The choice always stands; it's legal, just unusual enough to point out.
+const (
+	TrumpNotInHandWarn  = "warn"  // Log it and send the Trump Player a trump_warning
+	TrumpNotInHandAllow = "allow" // Accept it silently
+)
+
+// warnTrumpNotInHand flags a trump suit missing from the Trump Player's first cards.
+// In dark Hokm nobody has seen their cards yet, so any suit is as good as another.
+// The caller must hold game.Manager.Mu.
+func warnTrumpNotInHand(room *game.Room, player *game.Player, suit string) {
+	if room.Settings.GameMode == game.ModeDark {
+		return
+	}
+	if config.GetEnv("TRUMP_NOT_IN_HAND", TrumpNotInHandWarn) == TrumpNotInHandAllow {
+		return
+	}
+	for _, c := range player.Hand {
+		if c.Suit == suit {
+			return
+		}
+	}
+
+	log.Printf("Trump Player %s in room %s chose %s without holding any", player.ID, room.ID, suit)
+	player.Send(game.WSResponse{
+		Type: MessageTrumpWarning,
+		Payload: map[string]interface{}{
+			"trump_suit": suit,
+			"message":    "You chose a trump suit you don't hold.",
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+)
+
+func TestChooseTrumpRefusesAnythingButTheFourSuits(t *testing.T) {
+	for _, mode := range []string{game.ModeStandard, game.ModeDark} {
+		t.Run(mode, func(t *testing.T) {
+			fastGame(t)
+			t.Setenv("GAME_MODE", mode)
+			srv := newTestServer(t)
+
+			tb := joinTable(t, srv, 4)
+			tb.startGame(t)
+			trumpPlayer := tb.client(t, tb.ids[0])
+			trumpPlayer.expect(t, "choose_trump")
+
+			for _, suit := range []string{"stars", "", "Hearts"} {
+				trumpPlayer.send(t, "choose_trump", suit)
+				var got utils.APIError
+				decode(t, trumpPlayer.expect(t, MessageError), &got)
+				if got.Code != utils.CodeInvalidSuit {
+					t.Fatalf("choose_trump %q got %+v, want %s", suit, got, utils.CodeInvalidSuit)
+				}
+			}
+			game.Manager.Mu.RLock()
+			phase, trump := tb.room.Game.Phase, tb.room.Game.TrumpSuit
+			game.Manager.Mu.RUnlock()
+			if phase != game.PhaseWaitingTrump || trump != "" {
+				t.Fatalf("phase %s with trump %q after invalid suits, want the choice still open", phase, trump)
+			}
+			tb.chooseTrump(t, "hearts")
+		})
+	}
+}
+
+func TestTrumpNotInHandWarnsOnlyWhenConfigured(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		policy string
+		suit   string
+		warned bool
+	}{
+		{"a suit they don't hold", game.ModeStandard, TrumpNotInHandWarn, "hearts", true},
+		{"a suit they hold", game.ModeStandard, TrumpNotInHandWarn, "spades", false},
+		{"warnings turned off", game.ModeStandard, TrumpNotInHandAllow, "hearts", false},
+		{"dark Hokm", game.ModeDark, TrumpNotInHandWarn, "hearts", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fastGame(t)
+			t.Setenv("GAME_MODE", tt.mode)
+			t.Setenv("TRUMP_NOT_IN_HAND", tt.policy)
+			srv := newTestServer(t)
+
+			// The Trump Player's first cards are all spades
+			hands := wholeSuits(t)
+			utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+				return stackDeck(hands, settings.DealBatches())
+			}
+
+			tb := joinTable(t, srv, 4)
+			tb.startGame(t)
+			tb.chooseTrump(t, tt.suit)
+
+			// The warning goes out before the deal that chooseTrump waited for
+			trumpPlayer := tb.client(t, tb.ids[0])
+			if got := trumpPlayer.received(MessageTrumpWarning); got != tt.warned {
+				t.Fatalf("trump_warning sent = %v, want %v", got, tt.warned)
+			}
+			for _, c := range tb.clients[1:] {
+				if c.received(MessageTrumpWarning) {
+					t.Fatal("a player other than the Trump Player was warned")
+				}
+			}
+		})
+	}
+}
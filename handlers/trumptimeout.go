@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"log"
+	"time"
+)
+
+const MessageTrumpAutoSelected = "trump_auto_selected"
+
+// DefaultChooseTrumpTimeout is how long the Trump Player has to pick trump before it's
+// picked for them, unless CHOOSE_TRUMP_TIMEOUT says otherwise (0 disables it)
+const DefaultChooseTrumpTimeout = 30 * time.Second
+
+// armTrumpTimeout starts the clock on the Trump Player once the room is waiting for trump
+func armTrumpTimeout(room *game.Room) {
+	timeout := config.GetEnvDuration("CHOOSE_TRUMP_TIMEOUT", DefaultChooseTrumpTimeout)
+	if timeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	game.Manager.Mu.Lock()
+	cancelTrumpTimeout(room)
+	room.CancelTrump = cancel
+	game.Manager.Mu.Unlock()
+
+	goSafe("choose trump timeout in room "+room.ID, func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		autoSelectTrump(ctx, room)
+	})
+}
+
+// cancelTrumpTimeout stops the clock, e.g. once trump is chosen. The caller must hold game.Manager.Mu.
+func cancelTrumpTimeout(room *game.Room) {
+	if room != nil && room.CancelTrump != nil {
+		room.CancelTrump()
+		room.CancelTrump = nil
+	}
+}
+
+// autoSelectTrump picks the suit the Trump Player holds most of and carries on with the deal
+func autoSelectTrump(ctx context.Context, room *game.Room) {
+	game.Manager.Mu.Lock()
+	if ctx.Err() != nil || room.Game.Phase != game.PhaseWaitingTrump || room.Game.TrumpPlayer == nil {
+		game.Manager.Mu.Unlock()
+		return
+	}
+	room.CancelTrump = nil
+	if err := room.Game.Transition(game.PhaseDealing); err != nil {
+		game.Manager.Mu.Unlock()
+		log.Printf("Room %s: %v", room.ID, err)
+		return
+	}
+	trumpPlayer := room.Game.TrumpPlayer
+	suit := mostFrequentSuit(trumpPlayer.Hand)
+	deal := setTrumpSuit(room, trumpPlayer, suit)
+
+	log.Printf("⏰ Trump Player %s didn't choose in time in room %s, picking %s", trumpPlayer.ID, room.ID, suit)
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageTrumpAutoSelected,
+				Payload: map[string]interface{}{
+					"player_id":  trumpPlayer.ID,
+					"trump_suit": suit,
+				},
+			})
+		}
+	}
+	game.Manager.Mu.Unlock()
+
+	deal.send()
+}
+
+// mostFrequentSuit returns the suit with the most cards in the hand, the earlier suit
+// of hearts, diamonds, clubs and spades on a tie
+func mostFrequentSuit(hand []game.Card) string {
+	counts := make(map[string]int)
+	for _, c := range hand {
+		counts[c.Suit]++
+	}
+
+	best := "hearts"
+	for _, suit := range []string{"hearts", "diamonds", "clubs", "spades"} {
+		if counts[suit] > counts[best] {
+			best = suit
+		}
+	}
+	return best
+}
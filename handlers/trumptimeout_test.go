@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+	"hokm-backend/utils"
+)
+
+func TestTrumpTimeoutPicksTheMostFrequentSuitAndDeals(t *testing.T) {
+	fastGame(t)
+	t.Setenv("CHOOSE_TRUMP_TIMEOUT", "50ms")
+	srv := newTestServer(t)
+
+	// The Trump Player in seat 0 is dealt nothing but spades
+	hands := wholeSuits(t)
+	utils.RoundDeck = func(settings game.RoomSettings) []game.Card {
+		return stackDeck(hands, settings.DealBatches())
+	}
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+
+	picked := tb.clients[1].expect(t, MessageTrumpAutoSelected)
+	if picked["player_id"] != tb.ids[0] || picked["trump_suit"] != "spades" {
+		t.Fatalf("trump_auto_selected = %v, want spades for %s", picked, tb.ids[0])
+	}
+	waitFor(t, "play to begin", func() bool { return tb.room.Game.Phase == game.PhasePlaying })
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	if suit := tb.room.Game.TrumpSuit; suit != "spades" {
+		t.Fatalf("trump is %q, want spades", suit)
+	}
+	for _, p := range tb.room.Game.Players {
+		if len(p.Hand) != 13 {
+			t.Errorf("seat %d holds %d cards after the deal, want 13", p.Index, len(p.Hand))
+		}
+	}
+}
+
+func TestMostFrequentSuit(t *testing.T) {
+	tests := []struct {
+		name string
+		hand [][2]string
+		want string
+	}{
+		{"clear majority", [][2]string{{"clubs", "2"}, {"clubs", "9"}, {"hearts", "A"}, {"clubs", "K"}, {"spades", "3"}}, "clubs"},
+		{"tie goes to the earlier suit", [][2]string{{"spades", "2"}, {"spades", "9"}, {"diamonds", "A"}, {"diamonds", "K"}, {"hearts", "3"}}, "diamonds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hand []game.Card
+			for _, c := range tt.hand {
+				hand = append(hand, card(t, c[0], c[1]))
+			}
+			if got := mostFrequentSuit(hand); got != tt.want {
+				t.Fatalf("mostFrequentSuit(%v) = %s, want %s", hand, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"hokm-backend/config"
+	"hokm-backend/game"
+	"log"
+	"time"
+)
+
+const MessageTurnAutoPlayed = "turn_auto_played"
+
+// DefaultTurnTimeout is how long a player has to play a card before a bot plays it for
+// them, unless TURN_TIMEOUT says otherwise (0 disables it)
+const DefaultTurnTimeout = 30 * time.Second
+
+// DefaultBotMoveDelay is how long a seat played by a bot waits before playing, unless
+// BOT_MOVE_DELAY says otherwise
+const DefaultBotMoveDelay = time.Second
+
+// armTurnTimer starts the clock on the player whose turn it is. A seat with a BotLevel
+// is played by its bot after a short delay. The caller must hold game.Manager.Mu.
+func armTurnTimer(room *game.Room) {
+	cancelTurnTimer(room)
+	player := room.Game.CurrentPlayer()
+	if player == nil || room.Game.Phase != game.PhasePlaying {
+		return
+	}
+
+	timeout := config.GetEnvDuration("TURN_TIMEOUT", DefaultTurnTimeout)
+	if player.BotLevel != "" {
+		timeout = config.GetEnvDuration("BOT_MOVE_DELAY", DefaultBotMoveDelay)
+	} else if timeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	room.CancelTurn = cancel
+	playerID := player.ID
+
+	goSafe("turn timer in room "+room.ID, func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		autoPlayTurn(ctx, room, playerID)
+	})
+}
+
+// cancelTurnTimer stops the clock, e.g. once the card is played. The caller must hold game.Manager.Mu.
+func cancelTurnTimer(room *game.Room) {
+	if room != nil && room.CancelTurn != nil {
+		room.CancelTurn()
+		room.CancelTurn = nil
+	}
+}
+
+// autoPlayTurn plays the card the bot strategy picks for a player who ran out of time
+func autoPlayTurn(ctx context.Context, room *game.Room, playerID string) {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
+	player := room.Game.CurrentPlayer()
+	if ctx.Err() != nil || room.Game.Phase != game.PhasePlaying || player == nil || player.ID != playerID || len(player.Hand) == 0 {
+		return
+	}
+	room.CancelTurn = nil
+
+	level := botLevelOf(room, player)
+	card := game.StrategyFor(level).ChooseCard(room.Game, player.Hand)
+	if player.BotLevel == "" {
+		log.Printf("⏰ Player %s didn't play in time in room %s, playing %v (%s)", player.ID, room.ID, card, level)
+	}
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageTurnAutoPlayed,
+				Payload: map[string]interface{}{
+					"player_id": player.ID,
+					"card":      card,
+					"bot":       player.BotLevel != "",
+				},
+			})
+		}
+	}
+	playCardLocked(player, room, card, "", game.WSMessage{})
+}
+
+// botLevelOf returns how a bot plays the seat: the seat's own level for a bot seat,
+// the room's level for a player whose turn timed out
+func botLevelOf(room *game.Room, player *game.Player) string {
+	if player.BotLevel != "" {
+		return player.BotLevel
+	}
+	return room.Settings.BotLevel
+}
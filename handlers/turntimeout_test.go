@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestTurnTimeoutPlaysForThePlayer(t *testing.T) {
+	fastGame(t)
+	t.Setenv("TURN_TIMEOUT", "300ms")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	var leaderID string
+	game.Manager.Mu.RLock()
+	leaderID = tb.room.Game.CurrentPlayerID
+	game.Manager.Mu.RUnlock()
+
+	auto := tb.clients[1].expect(t, MessageTurnAutoPlayed)
+	if auto["player_id"] != leaderID || auto["bot"] != false {
+		t.Fatalf("turn_auto_played = %v, want the leader %s's turn played", auto, leaderID)
+	}
+	waitFor(t, "the leader's card on the table", func() bool {
+		g := tb.room.Game
+		return len(g.CurrentTrick) == 1 && len(g.TrickPlayOrder) == 1 && g.TrickPlayOrder[0].ID == leaderID
+	})
+}
+
+func TestBotSeatsPlayTheRoundOnTheirOwn(t *testing.T) {
+	fastGame(t)
+	t.Setenv("BOT_MOVE_DELAY", "0s")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+
+	levels := []string{game.BotEasy, game.BotNormal, game.BotHard, game.BotHard}
+	var trumpID string
+	game.Manager.Mu.Lock()
+	for i, p := range tb.room.Game.Players {
+		p.BotLevel = levels[i]
+	}
+	trumpID = tb.room.Game.TrumpPlayer.ID
+	game.Manager.Mu.Unlock()
+
+	tb.client(t, trumpID).send(t, "choose_trump", "spades")
+	tb.clients[0].expect(t, "round_winner")
+	tb.awaitSecondRound(t)
+}
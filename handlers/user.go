@@ -1,26 +1,40 @@
 package handlers
 
 import (
+	"hokm-backend/game"
 	"hokm-backend/models"
+	"hokm-backend/utils"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+// credentialsRequest is the body of /register and /login. It holds only the
+// fields a client may set, so a role can't be smuggled into a new account.
+type credentialsRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
 func Register(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req credentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidRequest, err.Error())
 		return
 	}
 
-	if err := user.HashPassword(user.Password); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+	user := models.User{Username: req.Username, Role: models.RolePlayer}
+	if err := user.HashPassword(req.Password); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternal, "Failed to hash password")
 		return
 	}
 
-	if err := models.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+	db, cancel := requestDB(c)
+	defer cancel()
+
+	if err := db.Create(&user).Error; err != nil {
+		respondDBError(c, err, http.StatusInternalServerError, utils.CodeInternal, "Failed to create user")
 		return
 	}
 
@@ -28,22 +42,108 @@ func Register(c *gin.Context) {
 }
 
 func Login(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req credentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidRequest, err.Error())
 		return
 	}
 
+	db, cancel := requestDB(c)
+	defer cancel()
+
 	var dbUser models.User
-	if err := models.DB.Where("username = ?", user.Username).First(&dbUser).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	if err := db.Where("username = ?", req.Username).First(&dbUser).Error; err != nil {
+		respondDBError(c, err, http.StatusUnauthorized, utils.CodeInvalidCredentials, "Invalid credentials")
+		return
+	}
+
+	if err := dbUser.CheckPassword(req.Password); err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.CodeInvalidCredentials, "Invalid credentials")
 		return
 	}
 
-	if err := dbUser.CheckPassword(user.Password); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	token, err := issueToken(&dbUser)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternal, "Failed to generate token")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Login successful"})
+	c.JSON(http.StatusOK, gin.H{"message": "Login successful", "token": token})
+}
+
+type changePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+func ChangePassword(c *gin.Context) {
+	var req changePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	db, cancel := requestDB(c)
+	defer cancel()
+
+	var dbUser models.User
+	if err := db.First(&dbUser, c.GetString("user_id")).Error; err != nil {
+		respondDBError(c, err, http.StatusUnauthorized, utils.CodeInvalidCredentials, "Invalid credentials")
+		return
+	}
+
+	if err := dbUser.CheckPassword(req.OldPassword); err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.CodeInvalidCredentials, "Invalid credentials")
+		return
+	}
+
+	if err := models.ValidatePassword(req.NewPassword); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.CodeWeakPassword, err.Error())
+		return
+	}
+
+	if err := dbUser.HashPassword(req.NewPassword); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternal, "Failed to hash password")
+		return
+	}
+
+	// Tokens issued before the change stop working
+	dbUser.TokenVersion++
+	if err := db.Save(&dbUser).Error; err != nil {
+		respondDBError(c, err, http.StatusInternalServerError, utils.CodeInternal, "Failed to update password")
+		return
+	}
+
+	token, err := issueToken(&dbUser)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, utils.CodeInternal, "Failed to generate token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully", "token": token})
+}
+
+// DeleteAccount deletes the authenticated user and anonymizes their game history.
+// Players still seated in a room must leave it first.
+func DeleteAccount(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if room, _ := game.Manager.FindRoomByUserID(userID); room != nil {
+		utils.RespondError(c, http.StatusConflict, utils.CodeInGame, "Leave your game before deleting your account")
+		return
+	}
+
+	db, cancel := requestDB(c)
+	defer cancel()
+
+	if err := models.DeleteUser(db, userID, c.GetString("username")); err != nil {
+		respondDBError(c, err, http.StatusInternalServerError, utils.CodeInternal, "Failed to delete account")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deleted"})
+}
+
+// issueToken signs a token carrying the user's ID, name, role and token version
+func issueToken(user *models.User) (string, error) {
+	return utils.GenerateToken(strconv.FormatUint(uint64(user.ID), 10), user.Username, user.Role, user.TokenVersion)
 }
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hokm-backend/middleware"
+	"hokm-backend/models"
+	"hokm-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// postJSON sends body to the handler the way the router would and returns the recorder
+func postJSON(t *testing.T, handler gin.HandlerFunc, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := gin.New()
+	router.POST("/", handler)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRegisterIgnoresRoleAndTokenVersion(t *testing.T) {
+	testDB(t)
+
+	for _, role := range []string{models.RoleAdmin, models.RoleCoach} {
+		name := "mallory-" + role
+		body := `{"username":"` + name + `","password":"secret-pass","role":"` + role + `","tokenversion":7,"TokenVersion":7}`
+		if rec := postJSON(t, Register, body); rec.Code != http.StatusOK {
+			t.Fatalf("register %s: status %d: %s", name, rec.Code, rec.Body)
+		}
+
+		var user models.User
+		if err := models.DB.Where("username = ?", name).First(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		if user.Role != models.RolePlayer || user.TokenVersion != 0 {
+			t.Fatalf("asking for %q created role %q with token version %d", role, user.Role, user.TokenVersion)
+		}
+
+		rec := postJSON(t, Login, `{"username":"`+name+`","password":"secret-pass","role":"admin"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("login %s: status %d: %s", name, rec.Code, rec.Body)
+		}
+		var resp struct {
+			Token string `json:"token"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		claims, err := utils.ParseToken(resp.Token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claims.Role != models.RolePlayer {
+			t.Fatalf("token of %s carries role %q, want %q", name, claims.Role, models.RolePlayer)
+		}
+	}
+}
+
+func TestRegisterRequiresUsernameAndPassword(t *testing.T) {
+	testDB(t)
+
+	for _, body := range []string{`{"username":"alice"}`, `{"password":"secret-pass"}`} {
+		if rec := postJSON(t, Register, body); rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestInvalidLoginErrorBody(t *testing.T) {
+	testDB(t)
+	if rec := postJSON(t, Register, `{"username":"alice","password":"secret-pass"}`); rec.Code != http.StatusOK {
+		t.Fatalf("register: status %d: %s", rec.Code, rec.Body)
+	}
+
+	want := `{"error":{"code":"` + utils.CodeInvalidCredentials + `","message":"Invalid credentials"}}`
+	for _, body := range []string{
+		`{"username":"alice","password":"wrong-pass"}`,
+		`{"username":"nobody","password":"secret-pass"}`,
+	} {
+		rec := postJSON(t, Login, body)
+		if rec.Code != http.StatusUnauthorized || rec.Body.String() != want {
+			t.Fatalf("%s: status %d with %s, want %d with %s", body, rec.Code, rec.Body, http.StatusUnauthorized, want)
+		}
+	}
+}
+
+// changePassword posts the passwords to POST /password/change with the token
+func changePassword(t *testing.T, token, oldPassword, newPassword string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := gin.New()
+	router.POST("/password/change", middleware.AuthRequired(), ChangePassword)
+	body := `{"old_password":"` + oldPassword + `","new_password":"` + newPassword + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/password/change", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestChangePassword(t *testing.T) {
+	testDB(t)
+	if rec := postJSON(t, Register, `{"username":"alice","password":"first-pass"}`); rec.Code != http.StatusOK {
+		t.Fatalf("register: status %d: %s", rec.Code, rec.Body)
+	}
+	login := func(password string) (int, string) {
+		rec := postJSON(t, Login, `{"username":"alice","password":"`+password+`"}`)
+		var resp struct {
+			Token string `json:"token"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp.Token
+	}
+	_, token := login("first-pass")
+
+	if rec := changePassword(t, token, "wrong-pass", "second-pass"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong old password: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if code, _ := login("first-pass"); code != http.StatusOK {
+		t.Fatalf("a rejected change broke the old password: login status %d", code)
+	}
+
+	if rec := changePassword(t, token, "first-pass", "second-pass"); rec.Code != http.StatusOK {
+		t.Fatalf("change: status %d: %s", rec.Code, rec.Body)
+	}
+	if code, _ := login("first-pass"); code != http.StatusUnauthorized {
+		t.Fatalf("old password after the change: login status %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, _ := login("second-pass"); code != http.StatusOK {
+		t.Fatalf("new password: login status %d, want %d", code, http.StatusOK)
+	}
+
+	// The token issued before the change is revoked
+	if rec := changePassword(t, token, "second-pass", "third-pass"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("old token after the change: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"hokm-backend/game"
+	"log"
+)
+
+const MessageKickVoteUpdate = "kick_vote_update"
+
+// handleKickVote records a vote to stop waiting for a disconnected or departed
+// player. Once a majority of the remaining players agree, the seat is resolved
+// according to the room's disconnect policy: the match is forfeited, or a bot
+// takes over the seat.
+func handleKickVote(voter *game.Player, room *game.Room, msg game.WSMessage) {
+	var targetID string
+	if !decodeData(voter, msg, &targetID) {
+		return
+	}
+	if targetID == voter.ID {
+		log.Println("Invalid kick vote target")
+		return
+	}
+
+	game.Manager.Mu.Lock()
+	target, team, ok := findKickTarget(room, targetID)
+	if !ok {
+		game.Manager.Mu.Unlock()
+		log.Printf("Player %s can't be voted out of room %s", targetID, room.ID)
+		return
+	}
+
+	if room.KickVotes == nil {
+		room.KickVotes = make(map[string]map[string]bool)
+	}
+	if room.KickVotes[targetID] == nil {
+		room.KickVotes[targetID] = make(map[string]bool)
+	}
+	room.KickVotes[targetID][voter.ID] = true
+
+	votes := len(room.KickVotes[targetID])
+	needed := votesNeeded(room, targetID)
+	passed := votes >= needed
+	if passed {
+		delete(room.KickVotes, targetID)
+	}
+	game.Manager.Mu.Unlock()
+
+	broadcastKickVoteUpdate(room, targetID, votes, needed, passed)
+	if !passed {
+		return
+	}
+
+	log.Printf("Vote passed to kick %s from room %s", targetID, room.ID)
+	if room.Settings.DisconnectPolicy == game.DisconnectForfeit {
+		forfeitGame(room, team)
+	} else if target != nil {
+		// A bot plays the seat so the table doesn't wait for a replacement
+		seatBot(room, target)
+	} else {
+		// The player already left: a bot takes the open seat and play resumes
+		seatBotInSavedSeat(room, targetID)
+	}
+
+	// Make sure a half-open connection of the kicked player doesn't linger
+	if target != nil {
+		closeConn(target, CloseKicked, "kicked")
+	}
+}
+
+// findKickTarget returns the disconnected seated player (or the team of a player who
+// already left and whose seat is still open) that may be voted out. Connected players
+// can't be kicked.
+func findKickTarget(room *game.Room, targetID string) (*game.Player, string, bool) {
+	for _, p := range room.Players {
+		if p.ID == targetID {
+			return p, p.Team, !p.Connected
+		}
+	}
+
+	// A departed player's seat is already open; the vote decides between a forfeit
+	// and a bot in the seat
+	if data, ok := room.SavedPlayers[targetID]; ok {
+		return nil, data.Team, true
+	}
+	return nil, "", false
+}
+
+// votesNeeded is a majority of the connected players other than the target
+func votesNeeded(room *game.Room, targetID string) int {
+	voters := 0
+	for _, p := range room.Players {
+		if p.ID != targetID && p.Connected {
+			voters++
+		}
+	}
+	return voters/2 + 1
+}
+
+func broadcastKickVoteUpdate(room *game.Room, targetID string, votes int, needed int, passed bool) {
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: MessageKickVoteUpdate,
+				Payload: map[string]interface{}{
+					"target_id": targetID,
+					"votes":     votes,
+					"needed":    needed,
+					"passed":    passed,
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+// dropSeat closes the connection of the player in the seat and waits until the server noticed
+func (tb *table) dropSeat(t *testing.T, seat int) *game.Player {
+	t.Helper()
+	tb.clients[seat].ws.Close()
+
+	var player *game.Player
+	waitFor(t, "the player to disconnect", func() bool {
+		for _, p := range tb.room.Players {
+			if p.ID == tb.ids[seat] && !p.Connected {
+				player = p
+				return true
+			}
+		}
+		return false
+	})
+	return player
+}
+
+func TestTwoKickVotesSeatABot(t *testing.T) {
+	fastGame(t)
+	t.Setenv("BOT_MOVE_DELAY", "0s")
+	t.Setenv("BOT_LEVEL", game.BotHard)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	kicked := tb.dropSeat(t, 2)
+
+	tb.clients[1].send(t, "vote_kick", kicked.ID)
+	first := tb.clients[3].expect(t, MessageKickVoteUpdate)
+	if first["votes"] != 1.0 || first["needed"] != 2.0 || first["passed"] != false {
+		t.Fatalf("kick_vote_update after one vote = %v, want 1 of 2 needed", first)
+	}
+
+	tb.clients[3].send(t, "vote_kick", kicked.ID)
+	second := tb.clients[3].expect(t, MessageKickVoteUpdate)
+	if second["votes"] != 2.0 || second["passed"] != true {
+		t.Fatalf("kick_vote_update after two votes = %v, want passed", second)
+	}
+	seated := tb.clients[0].expect(t, MessageBotSeated)
+	if seated["player_id"] != kicked.ID || seated["bot_level"] != game.BotHard {
+		t.Fatalf("bot_seated = %v, want a hard bot in the seat of %s", seated, kicked.ID)
+	}
+
+	// Seats 0 and 1 play, then the bot plays seat 2 on its own
+	tb.play(t, tb.firstLegal)
+	tb.play(t, tb.firstLegal)
+	waitFor(t, "the bot to play", func() bool {
+		order := tb.room.Game.TrickPlayOrder
+		return len(order) == 3 && order[2].ID == kicked.ID
+	})
+}
+
+func TestTwoKickVotesForfeitUnderTheForfeitPolicy(t *testing.T) {
+	fastGame(t)
+	t.Setenv("DISCONNECT_POLICY", game.DisconnectForfeit)
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+	kicked := tb.dropSeat(t, 2)
+
+	tb.clients[1].send(t, "vote_kick", kicked.ID)
+	tb.clients[3].send(t, "vote_kick", kicked.ID)
+
+	over := tb.clients[0].expect(t, "game_over")
+	if want := getOppositeTeam(kicked.Team); over["winner"] != want {
+		t.Fatalf("game_over = %v, want %s to win by forfeit", over, want)
+	}
+}
+
+func TestKickVoteSeatsABotInALeftSeatAndResumes(t *testing.T) {
+	fastGame(t)
+	t.Setenv("BOT_MOVE_DELAY", "0s")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	leaverID := tb.ids[2]
+	tb.clients[2].send(t, "leave_game", nil)
+	waitFor(t, "the game to pause", tb.room.Game.Halted)
+
+	tb.clients[1].send(t, "vote_kick", leaverID)
+	tb.clients[3].send(t, "vote_kick", leaverID)
+	seated := tb.clients[0].expect(t, MessageBotSeated)
+	if seated["player_id"] != leaverID {
+		t.Fatalf("bot_seated = %v, want a bot in the seat of %s", seated, leaverID)
+	}
+	waitFor(t, "the game to resume", func() bool { return tb.room.Game.Phase == game.PhasePlaying })
+
+	game.Manager.Mu.RLock()
+	if len(tb.room.SavedPlayers) != 0 || len(tb.room.Players) != 4 {
+		t.Errorf("%d saved seats and %d players after the vote, want 0 and 4", len(tb.room.SavedPlayers), len(tb.room.Players))
+	}
+	game.Manager.Mu.RUnlock()
+
+	// Seats 0 and 1 play, then the bot plays seat 2 on its own
+	tb.play(t, tb.firstLegal)
+	tb.play(t, tb.firstLegal)
+	waitFor(t, "the bot to play", func() bool {
+		order := tb.room.Game.TrickPlayOrder
+		return len(order) == 3 && order[2].ID == leaverID
+	})
+}
+
+func TestKickedClientGetsTheKickedCloseCode(t *testing.T) {
+	fastGame(t)
+	t.Setenv("BOT_MOVE_DELAY", "0s")
+	srv := newTestServer(t)
+
+	tb := joinTable(t, srv, 4)
+	tb.startGame(t)
+	tb.chooseTrump(t, "hearts")
+
+	// The server has given up on seat 2, but its socket is still half-open
+	game.Manager.Mu.Lock()
+	for _, p := range tb.room.Players {
+		if p.ID == tb.ids[2] {
+			p.Connected = false
+		}
+	}
+	game.Manager.Mu.Unlock()
+
+	tb.clients[1].send(t, "vote_kick", tb.ids[2])
+	tb.clients[3].send(t, "vote_kick", tb.ids[2])
+	if code := tb.clients[2].closeCode(t); code != CloseKicked {
+		t.Fatalf("kicked client was closed with %d, want %d", code, CloseKicked)
+	}
+}
@@ -1,22 +1,38 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hokm-backend/config"
 	"hokm-backend/game"
+	"hokm-backend/models"
 	"hokm-backend/utils"
 	"log"
+	"net"
 	"net/http"
 	"sort"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
-var playerCounter int
+// DealBatchInterval is the pause between the card batches dealt after trump is chosen.
+// It's a variable so tests can deal without waiting.
+var DealBatchInterval = 1 * time.Second
 
-const ReconnectTimeout = 30 * time.Second
+// ReadyTimeout is how long a full table waits for every "ready" before dealing anyway
+const ReadyTimeout = 20 * time.Second
+
+// DefaultReadLimit is the largest message in bytes a client may send, unless WS_READ_LIMIT says otherwise
+const DefaultReadLimit = 4096
+
+// DefaultPlayerIdleTimeout is how long a player may send nothing before being disconnected,
+// unless PLAYER_IDLE_TIMEOUT says otherwise (0 disables it)
+const DefaultPlayerIdleTimeout = 10 * time.Minute
 
 // Add new message types
 const (
@@ -25,6 +41,12 @@ const (
 	MessageGameState          = "game_state"
 	MessagePlayerLeft         = "player_left"
 	MessagePlayerReplaced     = "player_replaced"
+	MessageWaitingForReady    = "waiting_for_ready"
+	MessageRosterUpdate       = "roster_update"
+	MessageOutOfTurn          = "out_of_turn"
+	MessageHandSync           = "hand_sync"
+	MessageError              = "error"
+	MessageRoundStart         = "round_start"
 )
 
 var upgrader = websocket.Upgrader{
@@ -35,70 +57,188 @@ var upgrader = websocket.Upgrader{
 
 // HandleWebSocket handles WebSocket connections
 func HandleWebSocket(c *gin.Context) {
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Println("🔌 WebSocket upgrade failed:", err)
 		return
 	}
+	// Oversized messages fail the read and drop the connection instead of eating memory
+	ws.SetReadLimit(int64(config.GetEnvInt("WS_READ_LIMIT", DefaultReadLimit)))
+
+	conn := game.NewConn(ws)
 	log.Println("🌟 New WebSocket connection from:", conn.RemoteAddr())
-	defer conn.Close()
+	// Let what's still queued for the client go out before the socket closes
+	defer conn.CloseWith(websocket.CloseNormalClosure, "")
+
+	// Logged-in clients pass their token so the seat is tied to their account.
+	// Browsers can't read the HTTP status of a failed upgrade, so a bad token is
+	// reported with a close code instead.
+	var id identity
+	if token := c.Query("token"); token != "" {
+		claims, err := utils.ParseToken(token)
+		if err != nil {
+			closeSocket(conn, CloseInvalidToken, "invalid_token")
+			return
+		}
+		id = identity{UserID: claims.UserID, Role: claims.Role, Name: claims.Username, TokenVersion: claims.TokenVersion}
+	}
+
+	// ?watch=<room_id> joins as an observer instead of taking a seat, and
+	// ?room_id=<room_id>&role=spectator watches a game found through GET /rooms
+	if roomID := c.Query("watch"); roomID != "" {
+		watchRoom(conn, roomID, id, false)
+		return
+	}
+	if roomID := c.Query("room_id"); roomID != "" && c.Query("role") == "spectator" {
+		watchRoom(conn, roomID, id, true)
+		return
+	}
 
 	// Register the player
-	player := registerPlayer(conn)
+	player := registerPlayer(conn, id)
 	if player == nil {
 		return
 	}
 
 	// Handle incoming messages
+	readMessages(conn, player)
+}
+
+// identity is who a connection's token belongs to; the zero value is an anonymous connection
+type identity struct {
+	UserID       string
+	Role         string
+	Name         string // Username of the account, or the display name of a guest
+	TokenVersion int
+}
+
+// guest reports whether the token was issued by POST /guest rather than on login
+func (id identity) guest() bool {
+	return id.Role == models.RoleGuest
+}
+
+// playerName is the display name of a new seat: the username of an account, the name
+// on a guest token, or a generated name for anonymous connections
+func (id identity) playerName() string {
+	if id.Name != "" {
+		return id.Name
+	}
+	return NameGenerator()
+}
+
+// readMessages runs the read loop of a registered player until the connection fails
+func readMessages(conn *game.Conn, player *game.Player) {
+	defer recoverPlayerPanic(player)
+
+	idleTimeout := config.GetEnvDuration("PLAYER_IDLE_TIMEOUT", DefaultPlayerIdleTimeout)
+	idle := watchIdle(conn, idleTimeout)
+	defer idle.stop()
 	for {
-		var msg game.WSMessage
-		if err := conn.ReadJSON(&msg); err != nil {
-			log.Println("Read error:", err)
+		// Reading and decoding are separate steps: a failed read means the connection
+		// is gone, while a message that isn't valid JSON only costs that message
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			var netErr net.Error
+			switch {
+			case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+				log.Printf("👋 Player %s closed the connection", player.ID)
+			case websocket.IsUnexpectedCloseError(err):
+				log.Printf("Player %s's connection closed unexpectedly: %v", player.ID, err)
+			case errors.Is(err, websocket.ErrReadLimit):
+				log.Printf("🚫 Player %s sent a message over the read limit, disconnecting", player.ID)
+			case errors.As(err, &netErr) && netErr.Timeout():
+				log.Printf("💤 Player %s was idle for %v, disconnecting", player.ID, idleTimeout)
+				disconnectIdlePlayer(player)
+				return
+			default:
+				log.Println("Read error:", err)
+			}
 			unregisterPlayer(player)
 			break
 		}
+		// Every inbound message pushes the idle deadline back
+		idle.touch()
+
+		var msg game.WSMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Printf("Malformed message from %s: %v", player.ID, err)
+			sendError(conn, utils.CodeBadRequest, "Malformed message")
+			continue
+		}
 
 		// Process the message
 		processMessage(player, msg)
 	}
 }
 
+// initializeGame draws the Trump Player and deals them their first cards. The first
+// deal is claimed under the lock so a room is only ever dealt once, however many
+// callers see it fill up; the draw itself, with its pauses between cards, runs
+// without holding the lock.
 func initializeGame(room *game.Room) {
-	// Create and shuffle deck
-	deck := utils.NewDeck()
-	deck = utils.ShuffleDeck(deck)
-	room.Game.Deck = deck
+	game.Manager.Mu.Lock()
+	if room.Game.Phase != game.PhaseLobby || room.Game.TrumpPlayer != nil {
+		game.Manager.Mu.Unlock()
+		log.Printf("Room %s is already dealt, not starting it again", room.ID)
+		return
+	}
+
+	// Never deal to a table that isn't split 2-2
+	if err := room.ValidateTeams(); err != nil {
+		abortStart(room, "Can't start: "+err.Error()+". Waiting for players.")
+		game.Manager.Mu.Unlock()
+		return
+	}
+	if err := room.Game.Transition(game.PhaseDealing); err != nil {
+		log.Printf("Room %s: %v", room.ID, err)
+		abortStart(room, "The game couldn't start. Waiting for players.")
+		game.Manager.Mu.Unlock()
+		return
+	}
+	players := room.Game.DrawOrder() // The Ace draw starts after the dealer
+	observers := room.ObserverList()
+	settings := room.Settings
+	game.Manager.Mu.Unlock()
 
-	// Deal cards
-	var err error
-	room.Players, room.Game.Deck, room.Game.TrumpPlayer, err = utils.DealCards(
-		deck, room.Players, true, nil)
+	// Take the Round's deck, then deal
+	deck := utils.RoundDeck(settings)
+	_, deck, trumpPlayer, err := utils.DealCards(deck, players, observers, true, nil, settings)
 
+	game.Manager.Mu.Lock()
 	if err != nil {
 		log.Println("Error dealing cards:", err)
+		if err := room.Game.Transition(game.PhaseLobby); err != nil {
+			log.Printf("Room %s: %v", room.ID, err)
+		}
+		abortStart(room, "Dealing failed. Waiting for players.")
+		game.Manager.Mu.Unlock()
 		return
 	}
+	room.Game.Deck = deck
+	room.Game.TrumpPlayer = trumpPlayer
+	if !checkDeal(room, firstBatchCounts(room)) {
+		game.Manager.Mu.Unlock()
+		return
+	}
+	if err := room.Game.Transition(game.PhaseWaitingTrump); err != nil {
+		game.Manager.Mu.Unlock()
+		log.Printf("Room %s: %v", room.ID, err)
+		return
+	}
+	game.Manager.Mu.Unlock()
 
-	room.Game.TrumpPlayer.Conn.WriteJSON(game.WSResponse{
-		Type: "choose_trump",
-		Payload: map[string]interface{}{
-			"cards": room.Game.TrumpPlayer.Hand[:5], // First 5 cards for choosing the Trump Suit
-		},
-	})
-
-	// Notify players about trump player
-	// broadcastTrumpPlayer(room)
+	broadcastRoundStart(room)
+	sendChooseTrumpPrompt(room, trumpPlayer)
+	armTrumpTimeout(room)
 }
 
 // ****************************************************************
 // *********************** Replace Logic **************************
 // ****************************************************************
 
-// In handlers/websocket.go - findReplacementSpot()
-func findReplacementSpot() (*game.Room, *game.SavedPlayerData) {
-	game.Manager.Mu.RLock()
-	defer game.Manager.Mu.RUnlock()
-
+// findReplacementSpotLocked returns a saved seat waiting for a new player and its room.
+// The caller must hold game.Manager.Mu for writing, since it goes on to take the seat.
+func findReplacementSpotLocked() (*game.Room, *game.SavedPlayerData) {
 	// First pass: Find any saved player with their room ID
 	for _, room := range game.Manager.Rooms {
 		for _, data := range room.SavedPlayers {
@@ -111,30 +251,50 @@ func findReplacementSpot() (*game.Room, *game.SavedPlayerData) {
 	return nil, nil
 }
 
-func handleReplacement(room *game.Room, savedData *game.SavedPlayerData, conn *websocket.Conn) *game.Player {
+// handleReplacement seats the connection in a saved seat, if there is one. Finding
+// and taking the seat happen under one lock so no other connection can take it in between.
+func handleReplacement(conn *game.Conn, id identity) *game.Player {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
 
+	room, savedData := findReplacementSpotLocked()
+	if room == nil || savedData == nil {
+		return nil
+	}
 	if room.ID != savedData.RoomID {
 		log.Printf("Mismatched room ID during replacement")
 		return nil
 	}
 
-	game.Manager.Mu.Lock()
-	defer game.Manager.Mu.Unlock()
+	// Accounts already seated are turned away by registerPlayer
+	if seatedRoom, _ := game.Manager.FindRoomByUserIDLocked(id.UserID); seatedRoom != nil {
+		return nil
+	}
+
+	// The game shouldn't move on while a seat is saved, but if it did the saved hand
+	// can hold cards that were played since
+	hand, stale := room.Game.ReconcileHand(savedData.PlayerID, savedData.Hand)
+	if len(stale) > 0 {
+		log.Printf("Dropped %d stale cards from the saved hand of %s in room %s: %v", len(stale), savedData.PlayerID, room.ID, stale)
+	}
 
 	// Create new player with saved data
-	playerCounter++
 	newPlayer := &game.Player{
 		ID:        savedData.PlayerID, // Maintain same ID
-		Name:      fmt.Sprintf("Player%d", playerCounter),
+		Name:      id.playerName(),
 		Team:      savedData.Team,
-		Hand:      savedData.Hand,
-		Conn:      conn,
+		Hand:      hand,
 		Connected: true,
 		Index:     savedData.Index,
+		UserID:    id.UserID,
+		IsGuest:   id.guest(),
 	}
+	newPlayer.Attach(conn)
 
 	// Add to room
 	room.Players = append(room.Players, newPlayer)
+	room.Track(newPlayer)
+	room.UpdateBelowFull(time.Now())
 
 	// Sort players to maintain order
 	sort.Slice(room.Players, func(i, j int) bool {
@@ -142,26 +302,26 @@ func handleReplacement(room *game.Room, savedData *game.SavedPlayerData, conn *w
 	})
 
 	// Update game references
-	for i, p := range room.Game.Players {
-		if p.ID == savedData.PlayerID {
-			room.Game.Players[i] = newPlayer
-			break
-		}
-	}
+	room.Game.Seat(newPlayer)
 
 	// Remove from saved players
 	delete(room.SavedPlayers, savedData.PlayerID)
 
-	// Resume game if enough players
-	if len(room.Players) == 4 {
-		room.Game.IsGameOver = false
+	// Resume game if enough players and the teams are balanced
+	if len(room.Players) == room.Settings.Seats() {
+		if err := room.ValidateTeams(); err != nil {
+			log.Printf("Room %s stays paused: %v", room.ID, err)
+		} else {
+			room.Game.Resume()
 
-		// Notify all players about the new turn order
-		broadcastTurnUpdate(room)
+			// Notify all players about the new turn order
+			broadcastTurnUpdate(room)
+		}
 	}
 
 	// Notify all players about the replacement
 	broadcastReplacementNotification(newPlayer, room)
+	broadcastRosterUpdate(room)
 
 	// Broadcast the updated game state
 	broadcastGameStateAfterReplacement(room, newPlayer)
@@ -173,74 +333,151 @@ func handleReplacement(room *game.Room, savedData *game.SavedPlayerData, conn *w
 // ******************** Register ***********************
 // *****************************************************
 
-func registerPlayer(conn *websocket.Conn) *game.Player {
-	conn.WriteJSON(game.WSResponse{
-		Type:    "connection_ack",
-		Payload: map[string]interface{}{"status": "connecting"},
-	})
-
-	room, savedData := findReplacementSpot()
-	if room != nil && savedData != nil {
-		return handleReplacement(room, savedData, conn)
-	}
+func registerPlayer(conn *game.Conn, id identity) *game.Player {
+	conn.WriteJSON(connectionAck())
 
-	// First check for existing disconnected player
-	existingPlayer := findExistingPlayer(conn)
+	// A player coming back to their own disconnected seat goes first
+	existingPlayer := findExistingPlayer(conn, id.UserID)
 	if existingPlayer != nil {
 		return handleReconnectingPlayer(existingPlayer, conn)
 	}
 
+	// Saved seats are filled next; without one, join like any new player
+	if replacement := handleReplacement(conn, id); replacement != nil {
+		return replacement
+	}
+
 	// Create new player with proper locking
 	game.Manager.Mu.Lock()
 	defer game.Manager.Mu.Unlock()
 
+	// An account plays one game at a time
+	if seatedRoom, _ := game.Manager.FindRoomByUserIDLocked(id.UserID); seatedRoom != nil {
+		log.Printf("User %s is already seated in room %s, rejecting the new connection", id.UserID, seatedRoom.ID)
+		sendError(conn, utils.CodeAlreadyInGame, "You are already playing in another room")
+		closeSocket(conn, CloseAlreadyInGame, utils.CodeAlreadyInGame)
+		return nil
+	}
+
 	// Generate player ID and name
-	playerCounter++
-	playerID := strconv.Itoa(playerCounter)
+	playerID := uuid.NewString()
 
 	// Get or create room with available slot
-	room = getAvailableRoom()
+	room := getAvailableRoom()
 
-	// Determine team based on original player order
-	team := determineTeam(len(room.Players))
+	// Take the first free seat; the seat decides the team
+	seat := room.FreeSeat()
+	team := game.TeamForSeat(seat)
 
 	// Create new player with preserved index
 	newPlayer := &game.Player{
 		ID:        playerID,
-		Name:      fmt.Sprintf("Player%d", playerCounter),
+		Name:      id.playerName(),
 		Team:      team,
-		Conn:      conn,
 		Hand:      []game.Card{},
 		Connected: true,
-		Index:     len(room.Players), // Preserve position in original order
+		Index:     seat, // Preserve position in original order
+		UserID:    id.UserID,
+		IsGuest:   id.guest(),
 	}
+	newPlayer.Attach(conn)
 
 	// Add to room and game
 	room.Players = append(room.Players, newPlayer)
-	room.Game.Players = append(room.Game.Players, newPlayer)
+	room.Track(newPlayer)
+	room.UpdateBelowFull(time.Now())
+	room.SortPlayers()
+	room.Game.Seat(newPlayer)
 
 	// Send initial join message
 	sendJoinMessage(newPlayer, room)
+	broadcastRosterUpdate(room)
 
-	// Start game if room is full
-	if len(room.Players) == 4 {
-		initializeGame(room)
+	// Wait for everyone to be ready once the room is full
+	if len(room.Players) == room.Settings.Seats() {
+		broadcastWaitingForReady(room)
+		scheduleReadyTimeout(room)
 	}
 
 	return newPlayer
 }
 
+// *****************************************************
+// ******************** Ready Check ********************
+// *****************************************************
+
+// handlePlayerReady marks the player as ready and deals once the whole table is ready
+func handlePlayerReady(player *game.Player, room *game.Room) {
+	game.Manager.Mu.Lock()
+	player.Ready = true
+	start := len(room.Players) == room.Settings.Seats() && allPlayersReady(room) && !room.Started
+	var ctx context.Context
+	if start {
+		ctx = armStartCountdown(room)
+	} else {
+		broadcastWaitingForReady(room)
+	}
+	game.Manager.Mu.Unlock()
+
+	if start {
+		goSafe("start countdown in room "+room.ID, func() { runStartCountdown(ctx, room) })
+	}
+}
+
+// scheduleReadyTimeout starts the game after ReadyTimeout even if some players never sent "ready"
+func scheduleReadyTimeout(room *game.Room) {
+	time.AfterFunc(ReadyTimeout, func() {
+		defer recoverPanic("ready timeout in room " + room.ID)
+
+		// A room that was closed in the meantime is no longer in the manager
+		game.Manager.Mu.Lock()
+		if room.Started || len(room.Players) < room.Settings.Seats() || game.Manager.Rooms[room.ID] != room {
+			game.Manager.Mu.Unlock()
+			return
+		}
+		ctx := armStartCountdown(room)
+		notReady := notReadyPlayers(room)
+		game.Manager.Mu.Unlock()
+
+		log.Printf("Ready timeout in room %s, starting without %v", room.ID, notReady)
+		runStartCountdown(ctx, room)
+	})
+}
+
+func allPlayersReady(room *game.Room) bool {
+	return len(notReadyPlayers(room)) == 0
+}
+
+func notReadyPlayers(room *game.Room) []string {
+	notReady := []string{}
+	for _, p := range room.Players {
+		if !p.Ready {
+			notReady = append(notReady, p.ID)
+		}
+	}
+	return notReady
+}
+
 // Helper functions
-func findExistingPlayer(conn *websocket.Conn) *game.Player {
+func findExistingPlayer(conn *game.Conn, userID string) *game.Player {
 	game.Manager.Mu.RLock()
 	defer game.Manager.Mu.RUnlock()
 
-	// Simple IP-based session (replace with proper session management)
+	// Accounts are matched by user ID, anonymous players by IP
 	incomingIP := conn.RemoteAddr().String()
 
 	for _, room := range game.Manager.Rooms {
 		for _, p := range room.Players {
-			if !p.Connected && p.Conn != nil && p.Conn.RemoteAddr().String() == incomingIP {
+			if p.Connected {
+				continue
+			}
+			if p.UserID != "" {
+				if p.UserID == userID {
+					return p
+				}
+				continue
+			}
+			if p.Conn != nil && p.Conn.RemoteAddr().String() == incomingIP {
 				return p
 			}
 		}
@@ -249,28 +486,88 @@ func findExistingPlayer(conn *websocket.Conn) *game.Player {
 }
 
 func unregisterPlayer(player *game.Player) {
+	game.Manager.Mu.Lock()
 	player.Connected = false
+	if leaveQueueLocked(player) {
+		game.Manager.Mu.Unlock()
+		return
+	}
 	broadcastConnectionStatus(player, false)
+	// A deal in progress goes on for the others; the batches this player misses
+	// come back with the game_state of their reconnect
+	room := findPlayerRoomLocked(player)
+	ctx := armReconnectCountdown(player)
+
+	// The seat is held as long as the room says; without a room, for the default
+	timeout := game.DefaultReconnectTimeout
+	if room != nil {
+		timeout = room.Settings.ReconnectWait()
+	}
+	game.Manager.Mu.Unlock()
 
 	// Only remove if disconnected for too long
-	go func() {
-		time.Sleep(ReconnectTimeout)
-		if !player.Connected {
-			removePlayerPermanently(player)
+	goSafe("reconnect timeout for player "+player.ID, func() {
+		if !waitForReconnect(ctx, player, timeout) {
+			return
+		}
+		game.Manager.Mu.RLock()
+		reconnected := player.Connected
+		game.Manager.Mu.RUnlock()
+		if reconnected {
+			return
+		}
+
+		game.Manager.Mu.Lock()
+		room := findPlayerRoomLocked(player)
+		if room != nil && room.Settings.DisconnectPolicy == game.DisconnectForfeit && isGameInProgress(room) {
+			log.Printf("Player %s did not reconnect in room %s", player.ID, room.ID)
+			forfeitGameLocked(room, player.Team)
 		}
-	}()
+		game.Manager.Mu.Unlock()
+		removePlayerPermanently(player)
+	})
+}
+
+// isGameInProgress reports whether cards have been dealt and the match isn't finished
+func isGameInProgress(room *game.Room) bool {
+	return room.Game.TrumpPlayer != nil && !room.Game.Halted()
+}
+
+// forfeitGame ends the match in favor of the team opposing losingTeam, under game.Manager.Mu
+func forfeitGame(room *game.Room, losingTeam string) {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+	forfeitGameLocked(room, losingTeam)
+}
+
+// forfeitGameLocked is forfeitGame for callers already holding game.Manager.Mu
+func forfeitGameLocked(room *game.Room, losingTeam string) {
+	winner := getOppositeTeam(losingTeam)
+	log.Printf("%s forfeits the match in room %s", losingTeam, room.ID)
+
+	if err := room.Game.Transition(game.PhaseMatchOver); err != nil {
+		log.Printf("Can't forfeit the match in room %s: %v", room.ID, err)
+		return
+	}
+	room.Game.RoundScores[winner] = game.TargetScore
+	room.Game.RecordForfeit(losingTeam)
+	broadcastGameOver(room, winner)
+	saveGameHistory(room, winner)
 }
 
 // **************************************************************
 // *********************** Connection ***************************
 // **************************************************************
 
-func handleReconnectingPlayer(player *game.Player, conn *websocket.Conn) *game.Player {
+func handleReconnectingPlayer(player *game.Player, conn *game.Conn) *game.Player {
 	game.Manager.Mu.Lock()
 	defer game.Manager.Mu.Unlock()
 
-	// Update connection and status
-	player.Conn = conn
+	// Update connection and status, dropping whatever is left of the old connection
+	if player.Conn != nil && player.Conn != conn {
+		closeConn(player, CloseReplaced, "replaced")
+	}
+	player.Attach(conn)
 	player.Connected = true
 
 	// Find and update player in room
@@ -279,12 +576,9 @@ func handleReconnectingPlayer(player *game.Player, conn *websocket.Conn) *game.P
 			if p.ID == player.ID {
 				room.Players[i] = player
 				// Update game players reference
-				for j, gp := range room.Game.Players {
-					if gp.ID == player.ID {
-						room.Game.Players[j] = player
-						break
-					}
-				}
+				room.Game.Seat(player)
+				cancelReconnectCountdown(player, room)
+				reclaimFromBot(player, room)
 				sendReconnectNotifications(player, room)
 				return player
 			}
@@ -293,49 +587,47 @@ func handleReconnectingPlayer(player *game.Player, conn *websocket.Conn) *game.P
 	return nil
 }
 
-// Modify getAvailableRoom to create rooms without deadlock
+// getAvailableRoom returns a room a brand-new player can join. Rooms waiting on a
+// saved seat are left out: those seats are only filled through handleReplacement.
+// A room everyone left after its game began is dissolved rather than reused, so a
+// new player never lands in a stale game. The caller must hold game.Manager.Mu.
 func getAvailableRoom() *game.Room {
-
-	for _, room := range game.Manager.Rooms {
-		if len(room.SavedPlayers) > 0 && len(room.Players) < 4 {
-			return room
-		}
-	}
 	// Find first non-full, non-ended game room
-	for _, room := range game.Manager.Rooms {
-		if len(room.Players) < 4 && !room.Game.IsGameOver {
+	for id, room := range game.Manager.Rooms {
+		if isAbandonedRoom(room) {
+			delete(game.Manager.Rooms, id)
+			dissolveRoom(room, "Everyone left the room.")
+			continue
+		}
+		if len(room.Players) < room.Settings.Seats() && !room.Game.Halted() && len(room.SavedPlayers) == 0 {
 			return room
 		}
 	}
 	// Create new room if none available
-	roomID := game.GenerateRoomID()
-	room := &game.Room{
-		ID:      roomID,
-		Players: []*game.Player{},
-		Game:    game.NewGame(),
-	}
-	game.Manager.Rooms[roomID] = room
+	room := game.NewRoom(game.DefaultRoomSettings())
+	game.Manager.Rooms[room.ID] = room
 	return room
 }
 
-func determineTeam(playerCount int) string {
-	// Preserve original team assignment logic
-	if playerCount%2 == 0 {
-		return "team2"
+// isAbandonedRoom reports whether nobody is left in a room whose game already began
+func isAbandonedRoom(room *game.Room) bool {
+	if len(room.Players) > 0 || len(room.SavedPlayers) > 0 {
+		return false
 	}
-	return "team1"
+	return room.Started || room.Game.Phase != game.PhaseLobby
 }
 
 func sendJoinMessage(player *game.Player, room *game.Room) {
 	response := game.WSResponse{
 		Type: "join_room",
 		Payload: map[string]interface{}{
-			"room_id": room.ID,
-			"players": room.Players,
-			"your_id": player.ID,
+			"room_id":   room.ID,
+			"players":   maskPlayersFor(player.ID, room, room.Players),
+			"your_id":   player.ID,
+			"game_mode": room.Settings.GameMode,
 		},
 	}
-	if err := player.Conn.WriteJSON(response); err != nil {
+	if err := player.Send(response); err != nil {
 		log.Printf("🚨 Error sending join_room to %s: %v", player.ID, err)
 	} else {
 		log.Printf("✅ Sent join_room to %s in room %s", player.ID, room.ID)
@@ -344,12 +636,18 @@ func sendJoinMessage(player *game.Player, room *game.Room) {
 
 func sendReconnectNotifications(player *game.Player, room *game.Room) {
 	// Send full game state to reconnected player
-	sendGameState(player)
+	sendGameState(player, room)
+
+	// A Trump Player who dropped before choosing has to be asked again
+	if isWaitingForTrump(room) && room.Game.TrumpPlayer.ID == player.ID {
+		log.Printf("Re-prompting Trump Player %s to choose trump", player.ID)
+		sendChooseTrumpPrompt(room, player)
+	}
 
 	// Notify others about reconnection
 	for _, p := range room.Players {
 		if p.ID != player.ID && p.Connected {
-			p.Conn.WriteJSON(game.WSResponse{
+			p.Send(game.WSResponse{
 				Type: MessagePlayerReconnected,
 				Payload: map[string]interface{}{
 					"player_id": player.ID,
@@ -361,21 +659,51 @@ func sendReconnectNotifications(player *game.Player, room *game.Room) {
 }
 
 func removePlayerPermanently(player *game.Player) {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+
 	for _, room := range game.Manager.Rooms {
 		for i, p := range room.Players {
 			if p.ID == player.ID {
 				room.Players = append(room.Players[:i], room.Players[i+1:]...)
-				broadcastGameUpdate(room)
+				room.UpdateBelowFull(time.Now())
+				room.DropSwapRequests(player.ID)
+				cancelStartCountdown(room)
+				cancelRematch(room, "A player left.")
+				broadcastRosterUpdate(room)
+				if room.Started {
+					broadcastGameUpdateLocked(room)
+				}
 				break
 			}
 		}
 	}
 }
 
+// disconnectIdlePlayer gives up the seat of a player who stopped sending messages,
+// as if they had sent leave_game, and closes their connection
+func disconnectIdlePlayer(player *game.Player) {
+	if room := findPlayerRoom(player); room != nil {
+		handlePlayerLeave(player, room)
+	} else {
+		game.Manager.Mu.Lock()
+		leaveQueueLocked(player)
+		game.Manager.Mu.Unlock()
+	}
+	closeConn(player, CloseIdle, "idle")
+}
+
 func handlePlayerLeave(player *game.Player, room *game.Room) {
 	game.Manager.Mu.Lock()
 	defer game.Manager.Mu.Unlock()
 
+	// Under the discard policy the unfinished trick is taken back before the hand is saved
+	trickReturned := room.Settings.TrickOnLeave == game.TrickDiscard && len(room.Game.CurrentTrick) > 0
+	if trickReturned {
+		log.Printf("Returning the unfinished trick of room %s to the hands", room.ID)
+		room.Game.ReturnTrick()
+	}
+
 	// Save player state
 	if room.SavedPlayers == nil {
 		room.SavedPlayers = make(map[string]*game.SavedPlayerData)
@@ -389,6 +717,7 @@ func handlePlayerLeave(player *game.Player, room *game.Room) {
 		Index:     player.Index,
 		IsLeaving: true,
 		RoomID:    room.ID, // Track the room
+		SavedAt:   time.Now(),
 	}
 
 	// Remove from active players
@@ -398,41 +727,111 @@ func handlePlayerLeave(player *game.Player, room *game.Room) {
 			break
 		}
 	}
+	room.UpdateBelowFull(time.Now())
+	room.DropSwapRequests(player.ID)
 
 	// Pause the game
-	room.Game.IsGameOver = true
+	room.Game.Pause()
+	cancelDealing(room)
+	cancelStartCountdown(room)
+	cancelTrickAdvance(room)
+	cancelTrumpTimeout(room)
+	cancelTurnTimer(room)
+	cancelRematch(room, "A player left.")
 
 	// Notify other players
 	broadcastLeaveNotification(player, room)
+	broadcastRosterUpdate(room)
+
+	// Hands that got their card back need resyncing
+	if trickReturned {
+		for _, p := range room.Players {
+			if p.Connected {
+				sendHandSyncLocked(p, room)
+			}
+		}
+	}
 }
 
 // **************************************************************
 // ************************ Room Handler ************************
 // **************************************************************
 
-func sendGameState(player *game.Player) {
-	room := findPlayerRoom(player)
-	if room == nil {
-		return
-	}
+func sendGameState(player *game.Player, room *game.Room) {
 
 	// Create personalized game state
 	personalizedState := map[string]interface{}{
 		"trump_suit":     room.Game.TrumpSuit,
-		"scores":         room.Game.Scores,
-		"round_scores":   room.Game.RoundScores,
 		"current_trick":  room.Game.CurrentTrick,
-		"your_hand":      player.Hand,
+		"trick_plays":    room.Game.TrickPlays(),
+		"your_hand":      visibleHand(player, room),
 		"teams":          getTeamInfo(room),
-		"current_player": room.Game.Players[room.Game.CurrentPlayerIndex].ID,
+		"current_player": room.Game.CurrentPlayerID,
+		"hand_counts":    handCounts(room),
+		"phase":          room.Game.Phase,
+		"round_tricks":   room.Game.RoundTricks,
 	}
 
-	player.Conn.WriteJSON(game.WSResponse{
+	player.Send(game.WSResponse{
 		Type:    MessageGameState,
-		Payload: personalizedState,
+		Payload: withScores(personalizedState, room),
+	})
+}
+
+// sendHandSync sends a player just their own hand, a lighter resync than sendGameState.
+// The caller must not hold game.Manager.Mu; sendHandSyncLocked is for those who do.
+func sendHandSync(player *game.Player, room *game.Room) {
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	sendHandSyncLocked(player, room)
+}
+
+// sendHandSyncLocked is sendHandSync for callers holding game.Manager.Mu
+func sendHandSyncLocked(player *game.Player, room *game.Room) {
+	player.Send(game.WSResponse{
+		Type: MessageHandSync,
+		Payload: map[string]interface{}{
+			"hand":  visibleHand(player, room),
+			"count": len(player.Hand),
+		},
+	})
+}
+
+// sendChooseTrumpPrompt asks the Trump Player to pick trump from their first batch of cards,
+// or blind without any cards in dark Hokm
+func sendChooseTrumpPrompt(room *game.Room, trumpPlayer *game.Player) {
+	cards := []game.Card{}
+	if room.Settings.GameMode != game.ModeDark {
+		first := room.Settings.DealBatches()[0]
+		if first > len(trumpPlayer.Hand) {
+			first = len(trumpPlayer.Hand)
+		}
+		cards = game.SortHand(trumpPlayer.Hand[:first], "") // First batch for choosing the Trump Suit
+	}
+
+	trumpPlayer.Send(game.WSResponse{
+		Type: "choose_trump",
+		Payload: map[string]interface{}{
+			"cards":     cards,
+			"game_mode": room.Settings.GameMode,
+		},
 	})
 }
 
+// isWaitingForTrump reports whether the hands are out but no trump has been chosen yet
+func isWaitingForTrump(room *game.Room) bool {
+	return room.Game.Phase == game.PhaseWaitingTrump && room.Game.TrumpPlayer != nil
+}
+
+// handCounts returns how many cards each player still holds, keyed by player ID
+func handCounts(room *game.Room) map[string]int {
+	counts := make(map[string]int, len(room.Game.Players))
+	for _, p := range room.Game.Players {
+		counts[p.ID] = len(p.Hand)
+	}
+	return counts
+}
+
 func getTeamInfo(room *game.Room) map[string][]string {
 	teams := make(map[string][]string)
 	for _, p := range room.Players {
@@ -445,7 +844,11 @@ func getTeamInfo(room *game.Room) map[string][]string {
 func findPlayerRoom(player *game.Player) *game.Room {
 	game.Manager.Mu.RLock()
 	defer game.Manager.Mu.RUnlock()
+	return findPlayerRoomLocked(player)
+}
 
+// findPlayerRoomLocked is findPlayerRoom for callers already holding game.Manager.Mu
+func findPlayerRoomLocked(player *game.Player) *game.Room {
 	for _, room := range game.Manager.Rooms {
 		// Check active players
 		for _, p := range room.Players {
@@ -467,6 +870,43 @@ func findPlayerRoom(player *game.Player) *game.Room {
 // ************************* Handle Message ************************
 // *****************************************************************
 
+// activePhases are the phases in which play isn't halted
+var activePhases = []game.Phase{game.PhaseLobby, game.PhaseDealing, game.PhaseWaitingTrump, game.PhasePlaying, game.PhaseRoundOver}
+
+// actionPhases lists the phases each action is accepted in; nil accepts it in any
+// phase. Actions missing here are unknown.
+var actionPhases = map[string][]game.Phase{
+	"play_card":       {game.PhasePlaying},
+	"undo_play":       {game.PhasePlaying},
+	"choose_trump":    {game.PhaseWaitingTrump},
+	"ready":           {game.PhaseLobby},
+	"swap_seat":       {game.PhaseLobby},
+	"peek_last_trick": {game.PhaseWaitingTrump, game.PhasePlaying, game.PhaseRoundOver},
+	"rematch":         {game.PhaseMatchOver},
+	"requeue":         {game.PhaseMatchOver},
+	"leave_game":      activePhases,
+	"reaction":        activePhases,
+	"team_chat":       activePhases,
+	"reconnect":       nil,
+	"vote_kick":       nil,
+	"get_hand":        nil,
+	"replay":          nil,
+}
+
+// actionAllowed reports whether the action may be taken in the phase
+func actionAllowed(action string, phase game.Phase) bool {
+	phases, known := actionPhases[action]
+	if !known || phases == nil {
+		return true
+	}
+	for _, p := range phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
 // processMessage processes incoming WebSocket messages
 func processMessage(player *game.Player, msg game.WSMessage) {
 	if !player.Connected {
@@ -481,14 +921,22 @@ func processMessage(player *game.Player, msg game.WSMessage) {
 		return
 	}
 
-	// Block all game actions if paused
-	if room.Game.IsGameOver && msg.Action != "reconnect" {
-		player.Conn.WriteJSON(game.WSResponse{
-			Type: "game_paused",
-			Payload: map[string]interface{}{
-				"message": "Waiting for player replacement. Game paused.",
-			},
-		})
+	// Every action belongs to some phases of the game; a paused game gets its own notice
+	game.Manager.Mu.RLock()
+	phase := room.Game.Phase
+	game.Manager.Mu.RUnlock()
+	if !actionAllowed(msg.Action, phase) {
+		log.Printf("Rejected %s from %s in phase %s", msg.Action, player.ID, phase)
+		if phase == game.PhasePaused {
+			player.Send(game.WSResponse{
+				Type: "game_paused",
+				Payload: map[string]interface{}{
+					"message": "Waiting for player replacement. Game paused.",
+				},
+			})
+			return
+		}
+		sendError(player.Conn, utils.CodeWrongPhase, msg.Action+" isn't allowed while the game is "+string(phase))
 		return
 	}
 
@@ -496,276 +944,523 @@ func processMessage(player *game.Player, msg game.WSMessage) {
 	switch msg.Action {
 	case "play_card":
 		// Handle playing a card
-		cardData, ok := msg.Data.(map[string]interface{})
-		if !ok {
-			log.Println("Invalid card data")
+		var data PlayCardData
+		if !decodeData(player, msg, &data) {
 			return
 		}
 
 		// Validate card details
-		suit, ok := cardData["Suit"].(string)
-		if !ok || !isValidSuit(suit) {
-			log.Println("Invalid suit")
+		card, err := data.card(room.Settings.CardOrder)
+		if err != nil {
+			log.Println("Invalid card:", err)
+			sendError(player.Conn, utils.CodeInvalidCard, err.Error())
 			return
 		}
 
-		rank, ok := cardData["Rank"].(string)
-		if !ok || !isValidRank(rank) {
-			log.Println("Invalid rank")
+		log.Println("Playing card:", card)
+		playCard(player, room, card, data.MoveID, msg)
+	case "choose_trump":
+		// Handle choosing a trump suit
+		var trumpSuit string
+		if !decodeData(player, msg, &trumpSuit) {
 			return
 		}
 
-		value, ok := cardData["Value"].(float64)
-		if !ok {
-			log.Println("Invalid value type")
+		// Only the four suits are accepted, whatever the mode
+		if !game.IsValidSuit(trumpSuit) {
+			log.Println("Invalid trump suit:", trumpSuit)
+			sendError(player.Conn, utils.CodeInvalidSuit, "Invalid trump suit")
 			return
 		}
-		intValue := int(value)
 
-		if !isValidValue(rank, intValue) {
-			log.Println("Invalid value for rank")
+		// Trump is chosen once per Round, right after the first cards are out. Moving on
+		// to dealing under the lock keeps the choose_trump timeout from picking as well.
+		game.Manager.Mu.Lock()
+		if room.Game.TrumpPlayer == nil || player.ID != room.Game.TrumpPlayer.ID {
+			game.Manager.Mu.Unlock()
+			log.Println("Only the Trump Player can choose the trump suit")
+			sendError(player.Conn, utils.CodeNotTrumpPlayer, "Only the Trump Player can choose the trump suit")
+			return
+		}
+		if room.Game.Phase != game.PhaseWaitingTrump {
+			game.Manager.Mu.Unlock()
+			log.Printf("Ignoring choose_trump from %s in phase %s", player.ID, room.Game.Phase)
+			sendError(player.Conn, utils.CodeWrongPhase, "Trump can't be chosen now")
 			return
 		}
+		if err := room.Game.Transition(game.PhaseDealing); err != nil {
+			game.Manager.Mu.Unlock()
+			log.Printf("Room %s: %v", room.ID, err)
+			sendError(player.Conn, utils.CodeWrongPhase, "Trump can't be chosen now")
+			return
+		}
+		cancelTrumpTimeout(room)
+		warnTrumpNotInHand(room, player, trumpSuit)
+		deal := setTrumpSuit(room, player, trumpSuit)
+		game.Manager.Mu.Unlock()
 
-		card := game.Card{
-			Suit:  suit,
-			Rank:  rank,
-			Value: intValue,
+		sendAck(player, msg)
+		deal.send()
+	case "leave_game":
+		handlePlayerLeave(player, room)
+	case "ready":
+		handlePlayerReady(player, room)
+	case "get_hand":
+		sendHandSync(player, room)
+	case "peek_last_trick":
+		handlePeekLastTrick(player, room)
+	case "replay":
+		handleReplay(player, msg)
+	case "rematch":
+		handleRematch(player, room, msg)
+	case "requeue":
+		handleRequeue(player, room)
+	case "reaction":
+		handleReaction(player, room, msg)
+	case "team_chat":
+		handleTeamChat(player, room, msg)
+	case "swap_seat":
+		handleSwapSeat(player, room, msg)
+	case "vote_kick":
+		handleKickVote(player, room, msg)
+	case "undo_play":
+		undoPlay(player, room)
+	default:
+		// Handle unknown actions
+		log.Println("Unknown action:", msg.Action)
+		sendError(player.Conn, utils.CodeUnknownAction, "Unknown action: "+msg.Action)
+	}
+}
+
+// playCard puts the card on the trick and settles the trick, Round and match once
+// it's complete. The whole play happens under game.Manager.Mu so a leave, a
+// reconnect or a timer can't change the room halfway through. Once the card is
+// accepted the player gets an ack for msg.
+func playCard(player *game.Player, room *game.Room, card game.Card, moveID string, msg game.WSMessage) {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+	playCardLocked(player, room, card, moveID, msg)
+}
+
+// playCardLocked is playCard for callers already holding game.Manager.Mu
+func playCardLocked(player *game.Player, room *game.Room, card game.Card, moveID string, msg game.WSMessage) {
+	// Add to current trick
+	if err := room.Game.PlayMove(player.ID, moveID, card); err != nil {
+		// A retried play was already applied; resend the state instead of an error
+		if errors.Is(err, game.ErrDuplicateMove) {
+			log.Printf("Ignoring repeated move %s from %s", moveID, player.ID)
+			sendAck(player, msg)
+			sendGameState(player, room)
+			return
 		}
+		log.Println("Error playing card:", err)
+		if errors.Is(err, game.ErrNotYourTurn) {
+			sendOutOfTurn(player, room)
+		} else {
+			sendError(player.Conn, utils.CodeInvalidPlay, err.Error())
+		}
+		return
+	}
+	room.Game.RecordMove(player.ID, card)
+	sendAck(player, msg)
 
-		log.Println("Playing card:", card)
+	// The leader didn't wait for the cleared trick; this play's broadcast replaces it
+	cancelTrickAdvance(room)
+	cancelTurnTimer(room)
+
+	// Remove from hand
+	for i, c := range player.Hand {
+		if c.Suit == card.Suit && c.Rank == card.Rank {
+			player.Hand = append(player.Hand[:i], player.Hand[i+1:]...)
+			break
+		}
+	}
+	log.Printf("Player %s's updated hand: %v\n", player.Name, player.Hand)
+
+	// Only broadcast if trick is NOT complete
+	if len(room.Game.CurrentTrick) < len(room.Players) {
+		broadcastGameUpdateLocked(room)
+		broadcastTurnUpdate(room)
+	}
 
-		// Add to current trick
-		if err := room.Game.PlayCard(player.ID, card); err != nil {
-			log.Println("Error playing card:", err)
+	// Check if trick completed
+	if len(room.Game.CurrentTrick) == len(room.Players) {
+		winnerID, err := room.Game.DetermineTrickWinner(room.Players)
+		if err != nil {
+			log.Println("Error determining trick winner:", err)
 			return
 		}
+		log.Println("Trick winner:", winnerID)
 
-		// Remove from hand
-		for i, c := range player.Hand {
-			if c.Suit == card.Suit && c.Rank == card.Rank {
-				player.Hand = append(player.Hand[:i], player.Hand[i+1:]...)
+		// Validation should have stopped any renege; flag what slipped through
+		for _, r := range room.Game.AuditTrick() {
+			log.Printf("🚨 renege_detected in room %s: %s played %s of %s while holding %s", room.ID, r.PlayerID, r.Card.Rank, r.Card.Suit, r.LeadSuit)
+			room.Game.RecordRenege(r)
+		}
+
+		// Look the winner up among the game's seats, which drive the turns
+		var winningTeam string
+		for _, p := range room.Game.Players {
+			if p.ID == winnerID {
+				winningTeam = p.Team
 				break
 			}
 		}
-		log.Printf("Player %s's updated hand: %v\n", player.Name, player.Hand)
 
-		// Only broadcast if trick is NOT complete
-		if len(room.Game.CurrentTrick) < len(room.Players) {
-			broadcastGameUpdate(room)
-			broadcastTurnUpdate(room)
+		if winningTeam == "" {
+			log.Println("Could not determine winning team")
+			return
 		}
 
-		// Check if trick completed
-		if len(room.Game.CurrentTrick) == len(room.Players) {
-			winnerID := room.Game.DetermineTrickWinner(room.Players)
-			log.Println("Trick winner:", winnerID)
+		room.Game.UpdateScores(winningTeam, 1)
+		room.Game.RecordTrickWinner(winnerID)
+		room.Game.RecordTrick(winnerID)
+		log.Printf("Updated scores: %+v\n", room.Game.Scores)
 
-			var winningTeam string
-			for _, p := range room.Players {
-				if p.ID == winnerID {
-					winningTeam = p.Team
-					break
-				}
+		broadcastTrickComplete(room, winnerID, winningTeam)
+
+		// Check if the Round is over (enough tricks won by a team)
+		if roundOver, roundWinner := room.Game.IsRoundOver(); roundOver {
+			if err := room.Game.Transition(game.PhaseRoundOver); err != nil {
+				log.Printf("Room %s: %v", room.ID, err)
+				return
 			}
 
-			if winningTeam == "" {
-				log.Println("Could not determine winning team")
+			// The Round can't be scored without its Trump Player
+			if !hasTrumpPlayer(room) {
+				log.Printf("⚠️ Trump Player missing in room %s, pausing the Round", room.ID)
+				pauseGame(room, "Trump Player left. Game paused.")
 				return
 			}
 
-			room.Game.UpdateScores(winningTeam, 1)
-			log.Printf("Updated scores: %+v\n", room.Game.Scores)
-
-			// Inside the "play_card" case, replace the Round winner determination block with:
-			// Check if the Round is over (7 tricks won by a team)
-			if room.Game.Scores["team1"] >= 2 || room.Game.Scores["team2"] >= 2 {
-				// Determine teams
-				trumpTeam := room.Game.TrumpPlayer.Team
-				oppositeTeam := getOppositeTeam(trumpTeam)
-
-				var roundWinner string
-				var roundPoints int
-				var losingScore int
-
-				// Determine which team won the Round
-				if room.Game.Scores["team1"] >= 2 {
-					roundWinner = "team1"
-					losingScore = room.Game.Scores["team2"]
-				} else {
-					roundWinner = "team2"
-					losingScore = room.Game.Scores["team1"]
-				}
+			// Determine teams
+			trumpTeam := room.Game.TrumpPlayer.Team
+			oppositeTeam := getOppositeTeam(trumpTeam)
+
+			losingScore := room.Game.Scores[getOppositeTeam(roundWinner)]
+
+			// Determine points based on the room's scoring rules
+			roundPoints := room.Settings.Scoring.RoundPoints(losingScore, roundWinner == trumpTeam)
+			switch {
+			case losingScore == 0 && roundWinner == trumpTeam:
+				log.Printf("KOT! Trump team (%s) won %d-0. Awarding %d points", trumpTeam, room.Game.Scores[roundWinner], roundPoints)
+			case losingScore == 0 && roundWinner == oppositeTeam:
+				log.Printf("TRUMP KOT! Opposite team (%s) won %d-0. Awarding %d points", oppositeTeam, room.Game.Scores[roundWinner], roundPoints)
+			default:
+				log.Printf("Regular win. Awarding %d point(s) to %s", roundPoints, roundWinner)
+			}
+
+			// Update Round scores
+			room.Game.RoundScores[roundWinner] += roundPoints
+			if losingScore == 0 {
+				room.Game.Kots[roundWinner]++
+			}
 
-				// Determine points based on Hokm rules
-				switch {
-				case losingScore == 0 && roundWinner == trumpTeam:
-					// Kot: Trump team won 7-0
-					roundPoints = 2
-					log.Printf("KOT! Trump team (%s) won 7-0. Awarding 2 points", trumpTeam)
-				case losingScore == 0 && roundWinner == oppositeTeam:
-					// Trump Kot: Opposite team won 7-0 against Trump team
-					roundPoints = 3
-					log.Printf("TRUMP KOT! Opposite team (%s) won 7-0. Awarding 3 points", oppositeTeam)
-				default:
-					// Regular win (any score other than 7-0)
-					roundPoints = 1
-					log.Printf("Regular win. Awarding 1 point to %s", roundWinner)
+			// Broadcast Round winner with points and Trump team info
+			broadcastRoundWinner(room, roundWinner, roundPoints, trumpTeam)
+
+			// Check if the game is over (7 Rounds won by a team)
+			if matchOver, gameWinner := room.Game.IsMatchOver(); matchOver {
+				// Broadcast game over
+				if err := room.Game.Transition(game.PhaseMatchOver); err != nil {
+					log.Printf("Room %s: %v", room.ID, err)
 				}
+				broadcastGameOver(room, gameWinner)
+				saveGameHistory(room, gameWinner)
+				return
+			}
 
-				// Update Round scores
-				room.Game.RoundScores[roundWinner] += roundPoints
+			// Restart the game for the next Round
+			room.Game.ResetTrick()
+			goSafe("next round in room "+room.ID, func() { restartGameForNextRound(room, roundWinner) })
+		} else {
+			// The trick winner leads the next trick
+			if !room.Game.SetTrickLeader(winnerID) {
+				log.Printf("Trick winner %s is not seated in room %s", winnerID, room.ID)
+				return
+			}
 
-				// Broadcast Round winner with points and Trump team info
-				broadcastRoundWinner(room, roundWinner, roundPoints, trumpTeam)
+			room.Game.ResetTrick()
 
-				// Check if the game is over (7 Rounds won by a team)
-				if room.Game.RoundScores["team1"] >= 7 || room.Game.RoundScores["team2"] >= 7 {
-					// Determine the game winner
-					var gameWinner string
-					if room.Game.RoundScores["team1"] >= 7 {
-						gameWinner = "team1"
-					} else {
-						gameWinner = "team2"
-					}
+			// Leave the completed trick on screen for a moment before the cleaned state
+			scheduleTrickAdvance(room)
+		}
+	}
+}
 
-					// Broadcast game over
-					broadcastGameOver(room, gameWinner)
-					room.Game.IsGameOver = true
-					return
-				}
+// undoPlay takes the player's last card back off the trick, under game.Manager.Mu
+func undoPlay(player *game.Player, room *game.Room) {
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
 
-				// Restart the game for the next Round
-				restartGameForNextRound(room, roundWinner)
-				room.Game.ResetTrick()
-			} else {
-				// Update current player to trick winner
-				for i, p := range room.Players {
-					if p.ID == winnerID {
-						room.Game.CurrentPlayerIndex = i
-						break
-					}
-				}
+	card, err := room.Game.UndoLastPlay(player.ID)
+	if err != nil {
+		log.Println("Undo rejected:", err)
+		sendError(player.Conn, utils.CodeUndoRejected, err.Error())
+		return
+	}
+	log.Printf("Player %s took back %v\n", player.Name, card)
 
-				room.Game.ResetTrick()
+	broadcastPlayUndone(room, player, card)
+	broadcastGameUpdateLocked(room)
+	broadcastTurnUpdate(room)
+}
 
-				// Final broadcast with cleaned state
-				broadcastGameUpdate(room)
-				broadcastTurnUpdate(room)
-			}
-		}
+// setTrumpSuit records the Trump Player's suit and deals the rest of the hands. The
+// caller holds game.Manager.Mu and, once it's released, calls send on the result to
+// announce the suit and send the batches.
+func setTrumpSuit(room *game.Room, player *game.Player, trumpSuit string) *trumpDeal {
+	room.Game.TrumpSuit = trumpSuit
+	room.Game.RecordTrumpChoice(player.ID, trumpSuit)
+	log.Printf("Trump suit chosen: %s\n", trumpSuit)
+
+	deal := &trumpDeal{room: room, suit: trumpSuit}
+	deal.batches, deal.ok = dealRemainingCards(room)
+	if deal.ok {
+		deal.ctx, room.CancelDeal = context.WithCancel(context.Background())
+	}
+	return deal
+}
 
-	case "choose_trump":
-		// Handle choosing a trump suit
-		trumpSuit, ok := msg.Data.(string)
-		if !ok {
-			log.Println("Invalid trump suit data")
-			return
-		}
+// trumpDeal is a chosen Trump Suit with the rest of the hands, waiting to be sent
+type trumpDeal struct {
+	room    *game.Room
+	suit    string
+	batches []map[string][]game.Card
+	ctx     context.Context
+	ok      bool // False if the deal failed and the room was paused
+}
 
-		// Validate that the player is the Trump Player
-		if player.ID != room.Game.TrumpPlayer.ID {
-			log.Println("Only the Trump Player can choose the trump suit")
-			return
-		}
+// send broadcasts the chosen Trump Suit, then deals the batches from a separate
+// goroutine so the caller's connection keeps reading
+func (d *trumpDeal) send() {
+	game.Manager.Mu.RLock()
+	for _, p := range d.room.Players {
+		p.Send(game.WSResponse{
+			Type: "trump_suit_selected",
+			Payload: map[string]interface{}{
+				"trump_suit": d.suit,
+			},
+		})
+	}
+	game.Manager.Mu.RUnlock()
+
+	if d.ok {
+		goSafe("deal batches in room "+d.room.ID, func() { sendDealBatches(d.ctx, d.room, d.batches) })
+	}
+}
 
-		// Set the Trump Suit
-		room.Game.TrumpSuit = trumpSuit
-		log.Printf("Trump suit chosen: %s\n", trumpSuit)
+// *********************************************************
+// ****************** Dealing Logic ************************
+// *********************************************************
 
-		// Broadcast the chosen Trump Suit to all players
-		for _, p := range room.Players {
-			p.Conn.WriteJSON(game.WSResponse{
-				Type: "trump_suit_selected",
-				Payload: map[string]interface{}{
-					"trump_suit": trumpSuit,
-				},
-			})
-		}
+// dealRemainingCards fills every hand after trump is chosen following the room's
+// deal pattern (e.g. 5 cards to the others, then 4 and 4 to everyone) and returns
+// the batches to send. It's false if the deal failed. The caller must hold game.Manager.Mu.
+func dealRemainingCards(room *game.Room) ([]map[string][]game.Card, bool) {
+	trumpPlayerID := room.Game.TrumpPlayer.ID
 
-		// Step 1: Clear all players' hands except the Trump Player's initial 5 cards
-		for _, p := range room.Players {
-			if p.ID != room.Game.TrumpPlayer.ID {
-				p.Hand = []game.Card{}
-			}
+	// Clear all players' hands except the Trump Player's initial cards
+	for _, p := range room.Players {
+		if p.ID != trumpPlayerID {
+			p.Hand = []game.Card{}
 		}
+	}
 
-		// Step 2: Deal 5 cards to each of the other 3 players
-		log.Printf("Deck length before dealing 5 cards to other players: %d\n", len(room.Game.Deck))
+	log.Printf("Deck length before dealing: %d\n", len(room.Game.Deck))
+	var batches []map[string][]game.Card
+	if room.Settings.GameMode == game.ModeDark {
+		batches = dealFullHands(room)
+	} else {
+		batches = dealPatternBatches(room)
+	}
+	log.Printf("Deck length after dealing all batches: %d\n", len(room.Game.Deck))
+
+	// Every card of the deck should be in a hand now
+	fullHands := make(map[string]int)
+	for _, p := range room.Game.Players {
+		fullHands[p.ID] = room.Settings.CardsPerPlayer()
+	}
+	if !checkDeal(room, fullHands) {
+		return nil, false
+	}
+
+	// Log the hands of all players
+	for _, p := range room.Players {
+		log.Printf("Player %s (%s) hand: %v\n", p.Name, p.Team, p.Hand)
+	}
+	return batches, true
+}
+
+// firstBatchCounts is the expected hand sizes once only the Trump Player has their first batch
+func firstBatchCounts(room *game.Room) map[string]int {
+	return map[string]int{room.Game.TrumpPlayer.ID: room.Settings.DealBatches()[0]}
+}
+
+// checkDeal validates the deal unless VALIDATE_DEALS is false. A broken deal pauses
+// the room rather than letting play continue with the wrong cards.
+func checkDeal(room *game.Room, expected map[string]int) bool {
+	if config.GetEnv("VALIDATE_DEALS", "true") == "false" {
+		return true
+	}
+	if err := room.Game.ValidateDeal(expected, room.Settings.DeckSize()); err != nil {
+		log.Printf("🚨 Invalid deal in room %s: %v", room.ID, err)
+		pauseGame(room, "Dealing failed. Game paused.")
+		return false
+	}
+	return true
+}
+
+// dealPatternBatches deals by the room's pattern: the first batch goes to
+// everyone but the Trump Player, the rest to all players
+func dealPatternBatches(room *game.Room) []map[string][]game.Card {
+	pattern := room.Settings.DealBatches()
+	batches := make([]map[string][]game.Card, len(pattern))
+
+	for i, num := range pattern {
+		batches[i] = make(map[string][]game.Card)
 		for _, p := range room.Players {
-			if p.ID != room.Game.TrumpPlayer.ID {
-				cards := dealCards(room.Game.Deck, 5)
-				p.Hand = append(p.Hand, cards...)
-				room.Game.Deck = room.Game.Deck[5:]
-
-				// Broadcast the first batch of 5 cards to the player
-				p.Conn.WriteJSON(game.WSResponse{
-					Type: "deal_cards_batch_1",
-					Payload: map[string]interface{}{
-						"cards": cards,
-					},
-				})
+			if i == 0 && p.ID == room.Game.TrumpPlayer.ID {
+				continue
 			}
+			batches[i][p.ID] = dealToPlayer(room, p, num)
 		}
-		log.Printf("Deck length after dealing 5 cards to other players: %d\n", len(room.Game.Deck))
+	}
+	return batches
+}
 
-		// Add a 1-second delay before the next batch
-		time.Sleep(1 * time.Second)
+// dealFullHands completes every hand at once for dark Hokm. The Trump Player's
+// unseen first cards are revealed together with the rest of their hand.
+func dealFullHands(room *game.Room) []map[string][]game.Card {
+	batch := make(map[string][]game.Card)
+	for _, p := range room.Players {
+		dealToPlayer(room, p, room.Settings.CardsPerPlayer()-len(p.Hand))
+		batch[p.ID] = append([]game.Card{}, p.Hand...)
+	}
+	return []map[string][]game.Card{batch}
+}
 
-		// Step 3: Deal 4 cards to all 4 players (including the Trump Player)
-		log.Printf("Deck length before dealing 4 cards to all players: %d\n", len(room.Game.Deck))
-		for _, p := range room.Players {
-			cards := dealCards(room.Game.Deck, 4)
-			p.Hand = append(p.Hand, cards...)
-			room.Game.Deck = room.Game.Deck[4:]
+// dealToPlayer moves num cards from the top of the deck into the player's hand
+func dealToPlayer(room *game.Room, player *game.Player, num int) []game.Card {
+	cards := append([]game.Card{}, dealCards(room.Game.Deck, num)...)
+	player.Hand = append(player.Hand, cards...)
+	room.Game.Deck = room.Game.Deck[len(cards):]
+	return cards
+}
 
-			// Broadcast the second batch of 4 cards to the player
-			p.Conn.WriteJSON(game.WSResponse{
-				Type: "deal_cards_batch_2",
-				Payload: map[string]interface{}{
-					"cards": cards,
-				},
-			})
+// sendDealBatches sends one batch per DealBatchInterval, then moves the game from
+// dealing to playing and starts play with the Trump Player. A cancelled context
+// skips the remaining batches.
+//
+// The cards are already in the hands, so a player who misses a batch (gone, a failed
+// write or a new connection since the deal began) gets no more batches: their
+// reconnect sends game_state with the full hand instead, and further batches would
+// only show those cards twice.
+func sendDealBatches(ctx context.Context, room *game.Room, batches []map[string][]game.Card) {
+	game.Manager.Mu.RLock()
+	conns := make(map[string]*game.Conn, len(room.Players))
+	for _, p := range room.Players {
+		if p.Connected {
+			conns[p.ID] = p.Conn
 		}
-		log.Printf("Deck length after dealing 4 cards to all players: %d\n", len(room.Game.Deck))
+	}
+	game.Manager.Mu.RUnlock()
+	missed := make(map[string]bool)
+
+	// Without an interval the batches go out back to back
+	var tick <-chan time.Time
+	if DealBatchInterval > 0 {
+		ticker := time.NewTicker(DealBatchInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
 
-		// Add a 1-second delay before the next batch
-		time.Sleep(1 * time.Second)
+dealing:
+	for i, batch := range batches {
+		if ctx.Err() != nil {
+			log.Printf("Dealing cancelled in room %s", room.ID)
+			break
+		}
+		if i > 0 && tick != nil {
+			select {
+			case <-ctx.Done():
+				log.Printf("Dealing cancelled in room %s", room.ID)
+				break dealing
+			case <-tick:
+			}
+		}
 
-		// Step 4: Deal another 4 cards to all 4 players (including the Trump Player)
-		log.Printf("Deck length before dealing another 4 cards to all players: %d\n", len(room.Game.Deck))
+		game.Manager.Mu.RLock()
 		for _, p := range room.Players {
-			cards := dealCards(room.Game.Deck, 4)
-			p.Hand = append(p.Hand, cards...)
-			room.Game.Deck = room.Game.Deck[4:]
-
-			// Broadcast the third batch of 4 cards to the player
-			p.Conn.WriteJSON(game.WSResponse{
-				Type: "deal_cards_batch_3",
+			cards, ok := batch[p.ID]
+			if !ok || missed[p.ID] {
+				continue
+			}
+			if !p.Connected || p.Conn != conns[p.ID] {
+				log.Printf("%s missed deal batch %d in room %s, their hand comes with the resync", p.ID, i+1, room.ID)
+				missed[p.ID] = true
+				continue
+			}
+			err := p.Send(game.WSResponse{
+				Type: fmt.Sprintf("deal_cards_batch_%d", i+1),
 				Payload: map[string]interface{}{
-					"cards": cards,
+					"cards": game.SortHand(cards, room.Game.TrumpSuit),
 				},
 			})
+			if err != nil {
+				missed[p.ID] = true
+			}
 		}
-		log.Printf("Deck length after dealing another 4 cards to all players: %d\n", len(room.Game.Deck))
+		game.Manager.Mu.RUnlock()
+		sendObserverBatch(room, i+1, batch)
+	}
 
-		// Log the hands of all players
-		for _, p := range room.Players {
-			log.Printf("Player %s (%s) hand: %v\n", p.Name, p.Team, p.Hand)
-		}
+	game.Manager.Mu.Lock()
+	defer game.Manager.Mu.Unlock()
+	cancelDealing(room)
 
-		// Broadcast the updated game state
-		broadcastGameUpdate(room)
+	// Cards can only be played once every batch is out
+	if err := room.Game.Transition(game.PhasePlaying); err != nil {
+		log.Printf("Room %s: %v", room.ID, err)
+		return
+	}
 
-		// Start the game with the Trump Player
-		room.Game.CurrentPlayerIndex = indexOfPlayer(room.Players, room.Game.TrumpPlayer)
-		broadcastTurnUpdate(room)
-		// Add to processMessage switch case
-	case "leave_game":
-		handlePlayerLeave(player, room)
-	default:
-		// Handle unknown actions
-		log.Println("Unknown action:", msg.Action)
+	// Broadcast the updated game state
+	broadcastGameUpdateLocked(room)
+
+	// Start the game with the Trump Player
+	room.Game.SetRoundLeader()
+	broadcastTurnUpdate(room)
+}
+
+// sendObserverBatch shows observers a deal batch: how many cards each player got,
+// and for coaches, which ones
+func sendObserverBatch(room *game.Room, number int, batch map[string][]game.Card) {
+	counts := make(map[string]int, len(batch))
+	for id, cards := range batch {
+		counts[id] = len(cards)
+	}
+
+	game.Manager.Mu.RLock()
+	defer game.Manager.Mu.RUnlock()
+	for _, o := range room.Observers {
+		payload := map[string]interface{}{"counts": counts}
+		if coachView(o, room) {
+			payload["cards"] = batch
+		}
+		o.Conn.WriteJSON(game.WSResponse{
+			Type:    fmt.Sprintf("deal_cards_batch_%d", number),
+			Payload: payload,
+		})
+	}
+}
+
+// cancelDealing stops the dealing sequence of the room, if one is running. The caller
+// must hold game.Manager.Mu.
+func cancelDealing(room *game.Room) {
+	if room != nil && room.CancelDeal != nil {
+		room.CancelDeal()
+		room.CancelDeal = nil
 	}
 }
 
@@ -773,23 +1468,33 @@ func processMessage(player *game.Player, msg game.WSMessage) {
 // ****************** Restart Logic ************************
 // *********************************************************
 
+// restartGameForNextRound resets the room for the next Round under game.Manager.Mu,
+// releasing it only while the Trump Player's first cards are dealt
 func restartGameForNextRound(room *game.Room, roundWinner string) {
 	fmt.Println("Reset The Round...")
+	game.Manager.Mu.Lock()
 	// Increment the Round number
 	room.Game.CurrentRound++
 
 	// Reset scores for the new Round (only reset Scores, not RoundScores)
 	room.Game.Scores = make(map[string]int)
+	room.Game.TricksWon = make(map[string]int)
+	room.Game.RoundTricks = nil
+	room.Game.AppliedMoves = nil
+	room.Game.TrumpSuit = ""       // Chosen again by the Trump Player
+	room.Game.CurrentPlayerID = "" // Nobody has the turn until the deal is done
 
-	// Reset the deck and shuffle
-	room.Game.Deck = utils.NewDeck()
-	room.Game.Deck = utils.ShuffleDeck(room.Game.Deck)
+	// The new Round is dealt from a fresh utils.RoundDeck
+	room.Game.Deck = nil
 
 	// Clear all players' hands
 	for _, player := range room.Players {
 		player.Hand = []game.Card{}
 	}
 
+	// The deal moves one seat on every Round
+	room.Game.AdvanceDealer()
+
 	// Determine the new Trump Player if necessary
 	trumpTeam := room.Game.TrumpPlayer.Team
 	oppositeTeam := getOppositeTeam(trumpTeam)
@@ -806,38 +1511,71 @@ func restartGameForNextRound(room *game.Room, roundWinner string) {
 		log.Printf("Next Trump Index: %d", nextTrumpIndex)
 
 		// Broadcast the new Trump Player
+		resp := game.WSResponse{
+			Type: "trump_player_selected",
+			Payload: map[string]interface{}{
+				"trump_player_id": room.Game.TrumpPlayer.ID,
+			},
+		}
 		for _, p := range room.Players {
-			p.Conn.WriteJSON(game.WSResponse{
-				Type: "trump_player_selected",
-				Payload: map[string]interface{}{
-					"trump_player_id": room.Game.TrumpPlayer.ID,
-				},
-			})
+			p.Send(resp)
+		}
+		for _, o := range room.Observers {
+			o.Conn.WriteJSON(resp)
 		}
 	}
+	players, trumpPlayer, settings := room.Players, room.Game.TrumpPlayer, room.Settings
+	game.Manager.Mu.Unlock()
 
-	// Deal cards for the next Round (skip Ace selection)
-	var err error
-	room.Players, room.Game.Deck, room.Game.TrumpPlayer, err = utils.DealCards(room.Game.Deck, room.Players, false, room.Game.TrumpPlayer)
+	// Deal a fresh deck for the next Round (skip Ace selection)
+	_, deck, trumpPlayer, err := utils.DealCards(utils.RoundDeck(settings), players, nil, false, trumpPlayer, settings)
 	if err != nil {
 		log.Println("Error dealing cards:", err)
+		game.Manager.Mu.Lock()
+		pauseGame(room, "Dealing failed. Game paused.")
+		game.Manager.Mu.Unlock()
+		return
+	}
+
+	game.Manager.Mu.Lock()
+	room.Game.Deck = deck
+	room.Game.TrumpPlayer = trumpPlayer
+	if !checkDeal(room, firstBatchCounts(room)) {
+		game.Manager.Mu.Unlock()
 		return
 	}
 
 	// Notify the Trump Player to choose the Trump Suit
-	room.Game.TrumpPlayer.Conn.WriteJSON(game.WSResponse{
-		Type: "choose_trump",
-		Payload: map[string]interface{}{
-			"cards": room.Game.TrumpPlayer.Hand[:5], // First 5 cards for choosing the Trump Suit
-		},
-	})
+	if err := room.Game.Transition(game.PhaseWaitingTrump); err != nil {
+		game.Manager.Mu.Unlock()
+		log.Printf("Room %s: %v", room.ID, err)
+		return
+	}
+	broadcastRoundStart(room)
+	sendChooseTrumpPrompt(room, trumpPlayer)
+	game.Manager.Mu.Unlock()
 
-	// Broadcast the new game state
-	// broadcastGameUpdate(room)
+	armTrumpTimeout(room)
+}
 
-	// Start the game with the Trump Player
-	room.Game.CurrentPlayerIndex = indexOfPlayer(room.Players, room.Game.TrumpPlayer)
-	broadcastTurnUpdate(room)
+// hasTrumpPlayer reports whether the Trump Player is set and still seated in the room
+func hasTrumpPlayer(room *game.Room) bool {
+	return room.Game.TrumpPlayer != nil && indexOfPlayer(room.Players, room.Game.TrumpPlayer) != -1
+}
+
+// pauseGame stops play and tells the players why
+func pauseGame(room *game.Room, message string) {
+	room.Game.Pause()
+	for _, p := range room.Players {
+		if p.Connected {
+			p.Send(game.WSResponse{
+				Type: "game_paused",
+				Payload: map[string]interface{}{
+					"message": message,
+				},
+			})
+		}
+	}
 }
 
 // Helper function to get the opposite team
@@ -869,36 +1607,15 @@ func indexOfPlayer(players []*game.Player, player *game.Player) int {
 	return -1
 }
 
-func isValidSuit(suit string) bool {
-	validSuits := []string{"hearts", "diamonds", "clubs", "spades"}
-	for _, s := range validSuits {
-		if s == suit {
-			return true
-		}
-	}
-	return false
-}
-
-func isValidRank(rank string) bool {
-	validRanks := []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "A"}
-	for _, r := range validRanks {
-		if r == rank {
-			return true
-		}
-	}
-	return false
-}
-
-func isValidValue(rank string, value int) bool {
-	rankValues := map[string]int{
-		"2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9, "10": 10,
-		"J": 11, "Q": 12, "K": 13, "A": 14,
-	}
-	expectedValue, ok := rankValues[rank]
-	if !ok {
-		return false
-	}
-	return value == expectedValue
+// withScores adds the score fields to an outgoing payload. Scores holds the
+// tricks won in the current Round and RoundScores the Rounds won in the match,
+// so they are exposed to clients as "tricks" and "rounds".
+func withScores(payload map[string]interface{}, room *game.Room) map[string]interface{} {
+	payload["tricks"] = room.Game.Scores
+	payload["rounds"] = room.Game.RoundScores
+	payload["tricks_to_win_round"] = room.Settings.TricksToWinRound()
+	payload["rounds_to_win_match"] = game.TargetScore
+	return payload
 }
 
 // ***********************************************************
@@ -908,68 +1625,62 @@ func isValidValue(rank string, value int) bool {
 // broadcastGameOver notifies all players that the game is over
 func broadcastGameOver(room *game.Room, winner string) {
 	for _, player := range room.Players {
-		player.Conn.WriteJSON(game.WSResponse{
+		player.Send(game.WSResponse{
 			Type: "game_over",
-			Payload: map[string]interface{}{
+			Payload: withScores(map[string]interface{}{
 				"winner": winner,
-				"scores": room.Game.Scores,
-			},
+			}, room),
 		})
 	}
 }
 
-// broadcastGameUpdate sends the updated game state to all players in the room
+// broadcastGameUpdate sends the updated game state to all players in the room.
+// The caller must not hold game.Manager.Mu; broadcastGameUpdateLocked is for those who do.
 func broadcastGameUpdate(room *game.Room) {
 	game.Manager.Mu.RLock()
 	defer game.Manager.Mu.RUnlock()
-	for _, recipient := range room.Players {
-		// Create filtered player list
-		filteredPlayers := make([]*game.Player, len(room.Game.Players))
+	broadcastGameUpdateLocked(room)
+}
 
-		for i, p := range room.Game.Players {
-			playerCopy := *p
-			if p.ID != recipient.ID {
-				playerCopy.Hand = nil // Will be omitted in JSON
-			}
-			filteredPlayers[i] = &playerCopy
+// broadcastGameUpdateLocked is broadcastGameUpdate for callers holding game.Manager.Mu
+func broadcastGameUpdateLocked(room *game.Room) {
+	for _, recipient := range room.Players {
+		state := maskGameStateFor(recipient.ID, room)
+		if debugShowHands {
+			state = coachGameState(room)
 		}
-		// Add just the trump player ID
 		payload := map[string]interface{}{
-			"game": map[string]interface{}{
-				"players":            filteredPlayers,
-				"trump_player_id":    room.Game.TrumpPlayer.ID,
-				"trump_suit":         room.Game.TrumpSuit,
-				"current_trick":      room.Game.CurrentTrick,
-				"scores":             room.Game.Scores,
-				"current_player_idx": room.Game.CurrentPlayerIndex,
-			},
+			"game": state,
 		}
 
-		recipient.Conn.WriteJSON(game.WSResponse{
+		recipient.Send(game.WSResponse{
 			Type:    "game_update",
 			Payload: payload,
 		})
 	}
+
+	for _, observer := range room.Observers {
+		sendObserverUpdate(observer, room)
+	}
 }
 
 func broadcastGameStateAfterReplacement(room *game.Room, _ *game.Player) {
 	for _, player := range room.Players {
-		player.Conn.WriteJSON(game.WSResponse{
+		player.Send(game.WSResponse{
 			Type: "game_state_update",
-			Payload: map[string]interface{}{
+			Payload: withScores(map[string]interface{}{
 				// "player":             newPlayer.Hand,
-				"current_player_idx": room.Game.CurrentPlayerIndex,
+				"current_player_idx": room.Game.CurrentPlayerIndex(),
 				"trump_suit":         room.Game.TrumpSuit,
 				"current_trick":      room.Game.CurrentTrick,
-				"scores":             room.Game.Scores,
-			},
+			}, room),
 		})
 	}
 }
 
 func broadcastReplacementNotification(player *game.Player, room *game.Room) {
 	for _, p := range room.Players {
-		p.Conn.WriteJSON(game.WSResponse{
+		p.Send(game.WSResponse{
 			Type: MessagePlayerReplaced,
 			Payload: map[string]interface{}{
 				"old_player_id": player.ID,
@@ -991,7 +1702,7 @@ func broadcastConnectionStatus(player *game.Player, isConnected bool) {
 
 				for _, recipient := range room.Players {
 					if recipient.ID != player.ID {
-						recipient.Conn.WriteJSON(game.WSResponse{
+						recipient.Send(game.WSResponse{
 							Type: msgType,
 							Payload: map[string]interface{}{
 								"player_id": player.ID,
@@ -1009,7 +1720,7 @@ func broadcastConnectionStatus(player *game.Player, isConnected bool) {
 func broadcastLeaveNotification(player *game.Player, room *game.Room) {
 	for _, p := range room.Players {
 		if p.Connected {
-			p.Conn.WriteJSON(game.WSResponse{
+			p.Send(game.WSResponse{
 				Type: MessagePlayerLeft,
 				Payload: map[string]interface{}{
 					"player_id":         player.ID,
@@ -1021,29 +1732,134 @@ func broadcastLeaveNotification(player *game.Player, room *game.Room) {
 	}
 }
 
+func broadcastWaitingForReady(room *game.Room) {
+	for _, player := range room.Players {
+		player.Send(game.WSResponse{
+			Type: MessageWaitingForReady,
+			Payload: map[string]interface{}{
+				"not_ready": notReadyPlayers(room),
+			},
+		})
+	}
+}
+
+func broadcastPlayUndone(room *game.Room, player *game.Player, card game.Card) {
+	for _, p := range room.Players {
+		p.Send(game.WSResponse{
+			Type: "play_undone",
+			Payload: map[string]interface{}{
+				"player_id": player.ID,
+				"card":      card,
+			},
+		})
+	}
+}
+
+// broadcastRosterUpdate sends the seated players to everyone in the room
+func broadcastRosterUpdate(room *game.Room) {
+	roster := make([]map[string]interface{}, 0, len(room.Players))
+	for _, p := range room.Players {
+		roster = append(roster, map[string]interface{}{
+			"id":        p.ID,
+			"name":      p.Name,
+			"team":      p.Team,
+			"index":     p.Index,
+			"connected": p.Connected,
+		})
+	}
+
+	for _, player := range room.Players {
+		if player.Connected {
+			player.Send(game.WSResponse{
+				Type: MessageRosterUpdate,
+				Payload: map[string]interface{}{
+					"room_id": room.ID,
+					"players": roster,
+				},
+			})
+		}
+	}
+}
+
+// sendError reports a rejected action to the client with a stable code from utils
+func sendError(conn *game.Conn, code, message string) {
+	conn.WriteJSON(game.WSResponse{
+		Type:    MessageError,
+		Payload: utils.APIError{Code: code, Message: message},
+	})
+}
+
+// sendOutOfTurn tells a player who played out of turn whose turn it actually is
+func sendOutOfTurn(player *game.Player, room *game.Room) {
+	player.Send(game.WSResponse{
+		Type: MessageOutOfTurn,
+		Payload: map[string]interface{}{
+			"current_player": room.Game.CurrentPlayerID,
+			"message":        game.ErrNotYourTurn.Error(),
+		},
+	})
+}
+
+// broadcastRoundStart tells the room who deals and who is Trump Player this Round
+func broadcastRoundStart(room *game.Room) {
+	dealerID := ""
+	if dealer := room.Game.Dealer(); dealer != nil {
+		dealerID = dealer.ID
+	}
+	broadcastToRoom(room, MessageRoundStart, map[string]interface{}{
+		"round":           room.Game.CurrentRound,
+		"dealer_id":       dealerID,
+		"trump_player_id": room.Game.TrumpPlayer.ID,
+	})
+}
+
+// broadcastTurnUpdate tells everyone whose turn it is and starts that player's turn
+// timer. The caller must hold game.Manager.Mu.
 func broadcastTurnUpdate(room *game.Room) {
-	currentPlayer := room.Game.Players[room.Game.CurrentPlayerIndex]
+	if room.Game.CurrentPlayer() == nil {
+		return
+	}
+	armTurnTimer(room)
 	for _, player := range room.Players {
-		player.Conn.WriteJSON(game.WSResponse{
+		player.Send(game.WSResponse{
 			Type: "turn_update",
 			Payload: map[string]interface{}{
-				"current_player": currentPlayer.ID,
+				"current_player": room.Game.CurrentPlayerID,
 			},
 		})
 	}
 }
 
+func broadcastTrickComplete(room *game.Room, winnerID string, winningTeam string) {
+	// The Round ends once a team has enough tricks, or at the latest when the hands run out
+	roundOver, _ := room.Game.IsRoundOver()
+	lastTrick := roundOver || room.Game.AllHandsEmpty()
+
+	for _, player := range room.Players {
+		player.Send(game.WSResponse{
+			Type: "trick_complete",
+			Payload: withScores(map[string]interface{}{
+				"winner_id":              winnerID,
+				"winning_team":           winningTeam,
+				"trick":                  room.Game.CurrentTrick,
+				"tricks_won":             room.Game.TricksWon,
+				"is_last_trick_of_round": lastTrick,
+			}, room),
+		})
+	}
+}
+
 func broadcastRoundWinner(room *game.Room, winner string, points int, trumpTeam string) {
 	for _, player := range room.Players {
-		player.Conn.WriteJSON(game.WSResponse{
+		player.Send(game.WSResponse{
 			Type: "round_winner",
-			Payload: map[string]interface{}{
+			Payload: withScores(map[string]interface{}{
 				"winner":         winner,
 				"points_awarded": points,
 				"trump_team":     trumpTeam,
-				"round_scores":   room.Game.RoundScores,
 				"current_round":  room.Game.CurrentRound,
-			},
+				"tricks_won":     room.Game.TricksWon,
+			}, room),
 		})
 	}
 }
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"hokm-backend/config"
+	"hokm-backend/game"
+)
+
+// ProtocolVersion is bumped whenever a message changes in a way old clients can't handle
+const ProtocolVersion = 1
+
+// ServerVersion is reported to clients on connect. Release builds set it with
+// -ldflags "-X hokm-backend/handlers.ServerVersion=v1.2.3".
+var ServerVersion = "dev"
+
+// connectionAck is the first message on every connection. Besides the original
+// status it tells the client what this server supports, plus the optional
+// WELCOME_MESSAGE, so clients can adapt before joining a room.
+func connectionAck() game.WSResponse {
+	settings := game.DefaultRoomSettings()
+	payload := map[string]interface{}{
+		"status":           "connecting",
+		"server_version":   ServerVersion,
+		"protocol_version": ProtocolVersion,
+		"max_room_size":    settings.Seats(),
+		"features": map[string]bool{
+			"chat":       chatEnabled(),
+			"reactions":  reactionsEnabled(),
+			"spectators": true,
+			// A kick vote hands the seat to a bot unless the room forfeits instead
+			"bots": settings.DisconnectPolicy != game.DisconnectForfeit,
+		},
+	}
+	if message := config.GetEnv("WELCOME_MESSAGE", ""); message != "" {
+		payload["message"] = message
+	}
+	return game.WSResponse{Type: "connection_ack", Payload: payload}
+}
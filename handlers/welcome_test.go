@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestConnectionAckAdvertisesTheFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		features map[string]bool
+		seats    float64
+	}{
+		{"defaults", nil, map[string]bool{"chat": true, "reactions": true, "spectators": true, "bots": true}, 4},
+		{"chat off", map[string]string{"CHAT_ENABLED": "false"}, map[string]bool{"chat": false, "reactions": true, "spectators": true, "bots": true}, 4},
+		{"reactions off", map[string]string{"REACTIONS_ENABLED": "false"}, map[string]bool{"chat": true, "reactions": false, "spectators": true, "bots": true}, 4},
+		{"forfeit", map[string]string{"DISCONNECT_POLICY": game.DisconnectForfeit}, map[string]bool{"chat": true, "reactions": true, "spectators": true, "bots": false}, 4},
+		{"six seats", map[string]string{"TABLE_SIZE": "6"}, map[string]bool{"chat": true, "reactions": true, "spectators": true, "bots": true}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fastGame(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			srv := newTestServer(t)
+
+			ack := dial(t, srv, "").expect(t, "connection_ack")
+			var got struct {
+				Status          string          `json:"status"`
+				ProtocolVersion int             `json:"protocol_version"`
+				MaxRoomSize     float64         `json:"max_room_size"`
+				Features        map[string]bool `json:"features"`
+			}
+			decode(t, ack, &got)
+			if got.Status != "connecting" || got.ProtocolVersion != ProtocolVersion || got.MaxRoomSize != tt.seats {
+				t.Fatalf("connection_ack = %v", ack)
+			}
+			if len(got.Features) != len(tt.features) {
+				t.Fatalf("features = %v, want %v", got.Features, tt.features)
+			}
+			for name, want := range tt.features {
+				if got.Features[name] != want {
+					t.Fatalf("features = %v, want %v", got.Features, tt.features)
+				}
+			}
+		})
+	}
+}
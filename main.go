@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"hokm-backend/config"
-	"hokm-backend/game"
 	"hokm-backend/handlers"
+	"hokm-backend/middleware"
 	"hokm-backend/models"
+	"hokm-backend/utils"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +20,9 @@ import (
 func main() {
 	// Load configuration
 	config.LoadConfig()
+	if err := utils.CheckJWTSecret(); err != nil {
+		log.Fatalf("🔑 %v: set it to a long random string", err)
+	}
 
 	// Initialize database
 	db, err := models.InitDB()
@@ -20,22 +30,73 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Auto-migrate models
-	db.AutoMigrate(&models.User{}, &game.GameHistory{})
+	// Bring the schema up to date
+	if err := models.Migrate(db); err != nil {
+		log.Fatalf("💾 Database migration failed: %v", err)
+	}
 
 	if err := models.TestConnection(); err != nil {
 		log.Fatalf("💾 Database connection failed: %v", err)
 	}
 
+	// Clean up rooms that never fill up
+	handlers.StartRoomSweeper()
+
+	// Development only: show every hand to every player
+	handlers.EnableDebugHands()
+
 	// Set up Gin router
 	router := gin.Default()
+	router.Use(middleware.CORS(middleware.CORSConfigFromEnv()))
 
 	// Routes
-	router.POST("/register", handlers.Register)
-	router.POST("/login", handlers.Login)
+	registerLimit := config.GetEnvInt("REGISTER_RATE_LIMIT", 5)
+	loginLimit := config.GetEnvInt("LOGIN_RATE_LIMIT", 10)
+	router.POST("/register", middleware.RateLimit(registerLimit, time.Minute), handlers.Register)
+	router.POST("/login", middleware.RateLimit(loginLimit, time.Minute), handlers.Login)
+	guestLimit := config.GetEnvInt("GUEST_RATE_LIMIT", 10)
+	router.POST("/guest", middleware.RateLimit(guestLimit, time.Minute), handlers.Guest)
 	router.GET("/ws", handlers.HandleWebSocket)
+	router.GET("/rooms", handlers.ListRooms)
+	router.GET("/leaderboard", handlers.Leaderboard)
+	router.GET("/me/session", middleware.AuthRequired(), handlers.Session)
+	router.GET("/me/stats", middleware.AuthRequired(), handlers.Stats)
+	router.DELETE("/me", middleware.AuthRequired(), handlers.DeleteAccount)
+	router.POST("/password/change", middleware.AuthRequired(), handlers.ChangePassword)
+	router.GET("/history/:id/moves", handlers.GetGameMoves)
+	router.GET("/history/:id/replay", handlers.GetGameReplay)
+
+	admin := router.Group("/admin", middleware.AuthRequired(), middleware.AdminRequired())
+	admin.GET("/rooms", handlers.AdminListRooms)
+	admin.POST("/rooms/:id/terminate", handlers.AdminTerminateRoom)
+	admin.POST("/players/:id/move", handlers.AdminMovePlayer)
 
 	// Start server
-	log.Println("Starting server on :8080...")
-	router.Run(":8080")
+	srv := &http.Server{
+		Addr:    config.ListenAddr,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("Starting server on %s...", config.ListenAddr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	// Wait for SIGINT/SIGTERM
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	// Let players know before their games are cut off
+	handlers.NotifyShutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shut down: %v", err)
+	}
+	log.Println("Server stopped")
 }
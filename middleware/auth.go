@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"hokm-backend/models"
+	"hokm-backend/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthRequired rejects requests without a valid "Authorization: Bearer <token>" header
+// and stores the token's user in the context as "user_id", "username" and "role"
+func AuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenString := strings.TrimPrefix(header, "Bearer ")
+		if header == "" || tokenString == header {
+			utils.RespondError(c, http.StatusUnauthorized, utils.CodeMissingToken, "Missing token")
+			return
+		}
+
+		claims, err := utils.ParseToken(tokenString)
+		if err != nil {
+			utils.RespondError(c, http.StatusUnauthorized, utils.CodeInvalidToken, "Invalid token")
+			return
+		}
+
+		// Guests have no account to act on
+		if claims.Role == models.RoleGuest {
+			utils.RespondError(c, http.StatusForbidden, utils.CodeForbidden, "Guests have no account")
+			return
+		}
+
+		// Tokens issued before the last password change are revoked
+		var user models.User
+		if err := models.DB.WithContext(c.Request.Context()).Select("token_version").First(&user, claims.UserID).Error; err != nil || user.TokenVersion != claims.TokenVersion {
+			utils.RespondError(c, http.StatusUnauthorized, utils.CodeInvalidToken, "Invalid token")
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Next()
+	}
+}
+
+// AdminRequired rejects users without the admin role. It must run after AuthRequired.
+func AdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != models.RoleAdmin {
+			utils.RespondError(c, http.StatusForbidden, utils.CodeForbidden, "Admin access required")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"hokm-backend/config"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig lists what cross-origin browser clients are allowed to do
+type CORSConfig struct {
+	AllowedOrigins []string // "*" allows any origin
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// CORSConfigFromEnv reads the comma separated CORS_ALLOWED_ORIGINS,
+// CORS_ALLOWED_METHODS and CORS_ALLOWED_HEADERS variables
+func CORSConfigFromEnv() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: splitList(config.GetEnv("CORS_ALLOWED_ORIGINS", "")),
+		AllowedMethods: splitList(config.GetEnv("CORS_ALLOWED_METHODS", "GET,POST,DELETE,OPTIONS")),
+		AllowedHeaders: splitList(config.GetEnv("CORS_ALLOWED_HEADERS", "Authorization,Content-Type")),
+	}
+}
+
+// CORS adds the Access-Control headers for allowed origins and answers preflight requests
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin != "" && originAllowed(cfg.AllowedOrigins, origin) {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			c.Header("Vary", "Origin")
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
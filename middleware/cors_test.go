@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSAllowsOnlyListedOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(CORSConfig{
+		AllowedOrigins: []string{"https://hokm.example"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization"},
+	}))
+	router.POST("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/login", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodPost, "https://hokm.example")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://hokm.example" {
+		t.Fatalf("Access-Control-Allow-Origin = %q for an allowed origin", got)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("allowed request answered %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if got := request(http.MethodPost, "https://evil.example").Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("Access-Control-Allow-Origin = %q for an origin that isn't listed", got)
+	}
+
+	preflight := request(http.MethodOptions, "https://hokm.example")
+	if preflight.Code != http.StatusNoContent || preflight.Header().Get("Access-Control-Allow-Methods") != "GET, POST" {
+		t.Fatalf("preflight answered %d with methods %q", preflight.Code, preflight.Header().Get("Access-Control-Allow-Methods"))
+	}
+}
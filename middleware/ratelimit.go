@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"hokm-backend/utils"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bucket is a token bucket for a single client IP
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	capacity float64
+	rate     float64 // Tokens refilled per second
+	window   time.Duration
+}
+
+// RateLimit allows up to limit requests per window from each client IP and
+// answers 429 with a Retry-After header once a client runs out of tokens
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	rl := &rateLimiter{
+		buckets:  make(map[string]*bucket),
+		capacity: float64(limit),
+		rate:     float64(limit) / window.Seconds(),
+		window:   window,
+	}
+	go rl.sweep()
+
+	return func(c *gin.Context) {
+		allowed, retryAfter := rl.allow(c.ClientIP())
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			utils.RespondError(c, http.StatusTooManyRequests, utils.CodeRateLimited, "Too many requests")
+			return
+		}
+		c.Next()
+	}
+}
+
+// allow takes a token from the client's bucket, or reports how long until one is available
+func (rl *rateLimiter) allow(key string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: rl.capacity, lastSeen: now}
+		rl.buckets[key] = b
+	}
+
+	// Refill for the time elapsed since the last request
+	b.tokens = math.Min(rl.capacity, b.tokens+now.Sub(b.lastSeen).Seconds()*rl.rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / rl.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops buckets that have been idle long enough to be full again
+func (rl *rateLimiter) sweep() {
+	ticker := time.NewTicker(rl.window)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		rl.mu.Lock()
+		for key, b := range rl.buckets {
+			if time.Since(b.lastSeen) > rl.window {
+				delete(rl.buckets, key)
+			}
+		}
+		rl.mu.Unlock()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimitRejectsTheEleventhLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/login", RateLimit(10, time.Minute), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	login := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 1; i <= 10; i++ {
+		if w := login("10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("login %d answered %d, want %d", i, w.Code, http.StatusOK)
+		}
+	}
+	w := login("10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("11th login answered %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if retry := w.Header().Get("Retry-After"); retry == "" || retry == "0" {
+		t.Fatalf("Retry-After = %q, want the seconds until the next token", retry)
+	}
+
+	// Every client IP has its own bucket
+	if w := login("10.0.0.2"); w.Code != http.StatusOK {
+		t.Fatalf("login from another IP answered %d, want %d", w.Code, http.StatusOK)
+	}
+}
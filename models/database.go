@@ -1,24 +1,30 @@
 package models
 
 import (
+	"fmt"
 	"log"
 	"os"
 
+	"hokm-backend/config"
+
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
+// InitDB connects to the database selected by DB_DRIVER (postgres, mysql or sqlite).
+// DB_DSN overrides the connection string built from the DB_* variables.
 func InitDB() (*gorm.DB, error) {
-	dsn := "host=" + os.Getenv("DB_HOST") +
-		" user=" + os.Getenv("DB_USER") +
-		" password=" + os.Getenv("DB_PASSWORD") +
-		" dbname=" + os.Getenv("DB_NAME") +
-		" port=" + os.Getenv("DB_PORT") +
-		" sslmode=disable"
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	dialector, err := openDialector(config.GetEnv("DB_DRIVER", "postgres"), os.Getenv("DB_DSN"))
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 		return nil, err
@@ -28,6 +34,37 @@ func InitDB() (*gorm.DB, error) {
 	return db, nil
 }
 
+// openDialector returns the gorm dialector for the driver, building a DSN from
+// the DB_* variables when none is given
+func openDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "postgres":
+		if dsn == "" {
+			dsn = "host=" + os.Getenv("DB_HOST") +
+				" user=" + os.Getenv("DB_USER") +
+				" password=" + os.Getenv("DB_PASSWORD") +
+				" dbname=" + os.Getenv("DB_NAME") +
+				" port=" + os.Getenv("DB_PORT") +
+				" sslmode=disable"
+		}
+		return postgres.Open(dsn), nil
+	case "mysql":
+		if dsn == "" {
+			dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
+				os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"),
+				os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))
+		}
+		return mysql.Open(dsn), nil
+	case "sqlite":
+		if dsn == "" {
+			dsn = config.GetEnv("DB_NAME", "hokm.db")
+		}
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", driver)
+	}
+}
+
 func TestConnection() error {
 	sqlDB, err := DB.DB()
 	if err != nil {
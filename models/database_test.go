@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+// openTestDB opens a fresh in-memory sqlite database through InitDB and migrates it
+func openTestDB(t *testing.T) {
+	t.Helper()
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_DSN", "file:"+t.Name()+"?mode=memory&cache=shared")
+
+	db, err := InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		DB = nil
+	})
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() = %v", err)
+	}
+}
+
+func TestInitDBWithSqliteMigratesTheModels(t *testing.T) {
+	openTestDB(t)
+
+	for _, model := range []interface{}{&User{}, &game.GameHistory{}, &game.GameSeat{}} {
+		if !DB.Migrator().HasTable(model) {
+			t.Errorf("no table for %T", model)
+		}
+	}
+	if err := TestConnection(); err != nil {
+		t.Errorf("TestConnection() = %v", err)
+	}
+}
+
+func TestGameHistoryPlayersRoundTrip(t *testing.T) {
+	openTestDB(t)
+
+	saved := game.GameHistory{Players: []string{"alice", "bob, jr.", "carol", "dave"}, Winner: "team1", Score: 7}
+	if err := DB.Create(&saved).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded game.GameHistory
+	if err := DB.First(&loaded, saved.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Players) != 4 || loaded.Players[1] != "bob, jr." {
+		t.Fatalf("Players = %q, want %q", loaded.Players, saved.Players)
+	}
+}
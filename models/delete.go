@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+
+	"hokm-backend/game"
+
+	"gorm.io/gorm"
+)
+
+// DeletedUser replaces the name and ID of a deleted account in game history
+const DeletedUser = "deleted_user"
+
+// DeleteUser removes the account for good and anonymizes the games it played: its
+// game_seats rows and its name in each game's player list become DeletedUser.
+// The name is found by the seat the account played, so a guest or another account
+// that used the same name keeps it. The results themselves are kept so the other
+// players' stats don't change.
+func DeleteUser(db *gorm.DB, userID, username string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var seats []game.GameSeat
+		if err := tx.Where("user_id = ?", userID).Find(&seats).Error; err != nil {
+			return err
+		}
+
+		for _, seat := range seats {
+			var h game.GameHistory
+			if err := tx.Select("id", "players").First(&h, seat.GameHistoryID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				return err
+			}
+			anonymizeSeat(h.Players, seat.Seat, username)
+			if err := tx.Model(&h).Select("players").Updates(&h).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&game.GameSeat{}).Where("user_id = ?", userID).Update("user_id", DeletedUser).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&User{}, userID).Error
+	})
+}
+
+// anonymizeSeat replaces the name in the seat with DeletedUser. Rows saved before
+// seats were kept have no seat, so there the first entry with the username is used.
+func anonymizeSeat(players []string, seat *int, username string) {
+	if seat != nil {
+		if *seat >= 0 && *seat < len(players) {
+			players[*seat] = DeletedUser
+		}
+		return
+	}
+	for i, name := range players {
+		if name == username {
+			players[i] = DeletedUser
+			return
+		}
+	}
+}
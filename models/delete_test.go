@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestDeleteUserAnonymizesTheAccountsSeatOnly(t *testing.T) {
+	openTestDB(t)
+
+	user := User{Username: "alice", Password: "unused"}
+	if err := DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	userID := fmt.Sprint(user.ID)
+
+	// A guest also called alice sat in seat 0; the account played seat 2
+	seat := 2
+	history := game.GameHistory{
+		Players: []string{"alice", "bob", "alice", "dave"},
+		Seats: []game.GameSeat{
+			{UserID: userID, Seat: &seat, Team: "team1"},
+		},
+	}
+	// Saved before seats were kept: the name is the only way to find the account
+	legacy := game.GameHistory{
+		Players: []string{"carol", "alice", "bob", "dave"},
+		Seats:   []game.GameSeat{{UserID: userID, Team: "team2"}},
+	}
+	for _, h := range []*game.GameHistory{&history, &legacy} {
+		if err := DB.Create(h).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := DeleteUser(DB, userID, user.Username); err != nil {
+		t.Fatalf("DeleteUser() = %v", err)
+	}
+
+	for _, tt := range []struct {
+		id   uint
+		want []string
+	}{
+		{history.ID, []string{"alice", "bob", DeletedUser, "dave"}},
+		{legacy.ID, []string{"carol", DeletedUser, "bob", "dave"}},
+	} {
+		var got game.GameHistory
+		if err := DB.First(&got, tt.id).Error; err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got.Players, tt.want) {
+			t.Errorf("game %d players = %q, want %q", tt.id, got.Players, tt.want)
+		}
+	}
+
+	var left int64
+	DB.Model(&game.GameSeat{}).Where("user_id = ?", userID).Count(&left)
+	if left != 0 {
+		t.Errorf("%d game_seats rows still carry the deleted user's ID", left)
+	}
+	if err := DB.First(&User{}, user.ID).Error; err == nil {
+		t.Error("the account still exists")
+	}
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// Leaderboard metrics
+const (
+	MetricWins    = "wins"
+	MetricWinRate = "winrate"
+)
+
+// LeaderboardEntry is one ranked user of the leaderboard
+type LeaderboardEntry struct {
+	Rank     int     `json:"rank"`
+	UserID   string  `json:"user_id"`
+	Username string  `json:"username"`
+	Games    int64   `json:"games"`
+	Wins     int64   `json:"wins"`
+	WinRate  float64 `json:"win_rate"`
+}
+
+// Leaderboard ranks users by total wins, or by win rate among users with at least
+// minGames finished matches, and returns the top limit. Ties are broken by fewer games,
+// then by user ID, so the order is the same on every call.
+func Leaderboard(db *gorm.DB, metric string, limit, minGames int) ([]LeaderboardEntry, error) {
+	const wins = "SUM(CASE WHEN won THEN 1 ELSE 0 END)"
+
+	query := db.Table("game_seats").
+		Select("user_id, COUNT(*) AS games, "+wins+" AS wins").
+		Where("user_id <> ?", DeletedUser).
+		Group("user_id")
+
+	switch metric {
+	case MetricWins:
+		query = query.Order("wins DESC")
+	case MetricWinRate:
+		query = query.Having("COUNT(*) >= ?", minGames).
+			Order("1.0 * " + wins + " / COUNT(*) DESC").
+			Order("wins DESC")
+	default:
+		return nil, fmt.Errorf("unknown leaderboard metric %q", metric)
+	}
+
+	var entries []LeaderboardEntry
+	if err := query.Order("games ASC").Order("user_id ASC").Limit(limit).Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	// Usernames come from the users table; game_seats only keeps the ID
+	ids := make([]uint64, 0, len(entries))
+	for _, e := range entries {
+		if id, err := strconv.ParseUint(e.UserID, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	var users []User
+	if len(ids) > 0 {
+		if err := db.Select("id", "username").Where("id IN ?", ids).Find(&users).Error; err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[strconv.FormatUint(uint64(u.ID), 10)] = u.Username
+	}
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+		entries[i].Username = names[entries[i].UserID]
+		if entries[i].Games > 0 {
+			entries[i].WinRate = float64(entries[i].Wins) / float64(entries[i].Games)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestLeaderboardRanking(t *testing.T) {
+	openTestDB(t)
+
+	// Each user's finished matches as games and wins; bob ties alice on wins in more games
+	records := []struct {
+		name        string
+		games, wins int
+	}{
+		{"alice", 3, 3},
+		{"bob", 5, 3},
+		{"carol", 4, 2},
+		{"dave", 2, 0},
+	}
+	ids := make(map[string]string)
+	for _, r := range records {
+		user := User{Username: r.name, Password: "unused"}
+		if err := DB.Create(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		ids[r.name] = fmt.Sprint(user.ID)
+		for i := 0; i < r.games; i++ {
+			seat := game.GameSeat{UserID: ids[r.name], Team: "team1", Won: i < r.wins}
+			if err := DB.Create(&game.GameHistory{Seats: []game.GameSeat{seat}}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// Deleted accounts never rank, however many matches they won
+	for i := 0; i < 5; i++ {
+		seat := game.GameSeat{UserID: DeletedUser, Team: "team1", Won: true}
+		if err := DB.Create(&game.GameHistory{Seats: []game.GameSeat{seat}}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		metric string
+		limit  int
+		want   []string
+	}{
+		{MetricWins, 10, []string{"alice", "bob", "carol", "dave"}},
+		{MetricWins, 2, []string{"alice", "bob"}},
+		{MetricWinRate, 10, []string{"alice", "bob", "carol"}}, // dave is under 3 games
+	}
+	for _, tt := range tests {
+		board, err := Leaderboard(DB, tt.metric, tt.limit, 3)
+		if err != nil {
+			t.Fatalf("Leaderboard(%s, %d) = %v", tt.metric, tt.limit, err)
+		}
+		var got []string
+		for i, e := range board {
+			if e.Rank != i+1 || e.UserID != ids[e.Username] {
+				t.Errorf("Leaderboard(%s, %d) entry %d = %+v", tt.metric, tt.limit, i, e)
+			}
+			got = append(got, e.Username)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Leaderboard(%s, %d) ranked %v, want %v", tt.metric, tt.limit, got, tt.want)
+		}
+	}
+
+	if _, err := Leaderboard(DB, "kots", 10, 3); err == nil {
+		t.Error("Leaderboard(kots) succeeded, want an unknown metric error")
+	}
+}
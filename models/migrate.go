@@ -0,0 +1,38 @@
+package models
+
+import (
+	"log"
+
+	"hokm-backend/game"
+
+	"gorm.io/gorm"
+)
+
+// Migrate brings the schema up to date: it converts columns whose type changed in a
+// way AutoMigrate can't handle, then auto-migrates every model
+func Migrate(db *gorm.DB) error {
+	if err := migrateHistoryPlayers(db); err != nil {
+		return err
+	}
+	return db.AutoMigrate(&User{}, &game.GameHistory{}, &game.GameSeat{})
+}
+
+// migrateHistoryPlayers converts game_histories.players from the Postgres text[] it
+// used to be to the JSON text GameHistory now stores. Left to AutoMigrate, the
+// column would become text holding array literals such as {a,b}, which isn't JSON.
+func migrateHistoryPlayers(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" || !db.Migrator().HasTable(&game.GameHistory{}) {
+		return nil
+	}
+
+	var dataType string
+	err := db.Raw(`SELECT data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'game_histories' AND column_name = 'players'`).
+		Scan(&dataType).Error
+	if err != nil || dataType != "ARRAY" {
+		return err
+	}
+
+	log.Println("Converting game_histories.players from text[] to JSON")
+	return db.Exec(`ALTER TABLE game_histories ALTER COLUMN players TYPE text USING array_to_json(players)::text`).Error
+}
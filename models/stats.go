@@ -0,0 +1,32 @@
+package models
+
+import "gorm.io/gorm"
+
+// Stats are a user's results over every finished match they played
+type Stats struct {
+	Games   int64   `json:"games"`
+	Wins    int64   `json:"wins"`
+	Losses  int64   `json:"losses"`
+	WinRate float64 `json:"win_rate"` // Wins / Games, 0 with no games
+	Kots    int64   `json:"kots"`
+}
+
+// UserStats aggregates the game_seats rows stored with each GameHistory for the user
+func UserStats(db *gorm.DB, userID string) (Stats, error) {
+	var stats Stats
+	err := db.Table("game_seats").
+		Select("COUNT(*) AS games, "+
+			"COALESCE(SUM(CASE WHEN won THEN 1 ELSE 0 END), 0) AS wins, "+
+			"COALESCE(SUM(kots), 0) AS kots").
+		Where("user_id = ?", userID).
+		Scan(&stats).Error
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats.Losses = stats.Games - stats.Wins
+	if stats.Games > 0 {
+		stats.WinRate = float64(stats.Wins) / float64(stats.Games)
+	}
+	return stats, nil
+}
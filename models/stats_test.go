@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"hokm-backend/game"
+)
+
+func TestUserStatsWinRate(t *testing.T) {
+	openTestDB(t)
+
+	// alice won two of three matches, with a Kot in one of them; bob won the other
+	for _, seats := range [][]game.GameSeat{
+		{{UserID: "1", Team: "team1", Won: true, Kots: 1}, {UserID: "2", Team: "team2"}},
+		{{UserID: "1", Team: "team2", Won: true}, {UserID: "2", Team: "team1"}},
+		{{UserID: "1", Team: "team1"}, {UserID: "2", Team: "team2", Won: true}},
+	} {
+		if err := DB.Create(&game.GameHistory{Seats: seats}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		userID string
+		want   Stats
+	}{
+		{"1", Stats{Games: 3, Wins: 2, Losses: 1, WinRate: 2.0 / 3, Kots: 1}},
+		{"2", Stats{Games: 3, Wins: 1, Losses: 2, WinRate: 1.0 / 3}},
+		{"3", Stats{}},
+	}
+	for _, tt := range tests {
+		got, err := UserStats(DB, tt.userID)
+		if err != nil {
+			t.Fatalf("UserStats(%s) = %v", tt.userID, err)
+		}
+		if got != tt.want {
+			t.Errorf("UserStats(%s) = %+v, want %+v", tt.userID, got, tt.want)
+		}
+	}
+}
@@ -1,14 +1,37 @@
 package models
 
 import (
+	"errors"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// MinPasswordLength is the shortest password accepted when setting a new password
+const MinPasswordLength = 8
+
+// User roles
+const (
+	RolePlayer = "player"
+	RoleAdmin  = "admin" // Can inspect and terminate rooms through /admin
+	RoleCoach  = "coach" // Sees every hand when watching a room
+	RoleGuest  = "guest" // Short-lived token from POST /guest, no account behind it
+)
+
 type User struct {
 	gorm.Model
-	Username string `gorm:"unique;not null"`
-	Password string `gorm:"not null"`
+	Username     string `gorm:"unique;not null"`
+	Password     string `gorm:"not null"`
+	TokenVersion int    `gorm:"not null;default:0" json:"-"` // Bumped to invalidate previously issued tokens
+	Role         string `gorm:"not null;default:player" json:"-"`
+}
+
+// ValidatePassword checks a new password against the password policy
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return errors.New("password must be at least 8 characters")
+	}
+	return nil
 }
 
 func (u *User) HashPassword(password string) error {
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"hokm-backend/game"
+)
+
+// seededDeal deals the first cards of a 2v2 match with game.Random seeded by seed and
+// returns the seat of the Trump Player, their hand and what's left of the deck
+func seededDeal(t *testing.T, seed int64) (int, []game.Card, []game.Card) {
+	t.Helper()
+	defer func(r game.RNG) { game.Random = r }(game.Random)
+	game.Random = game.NewRNG(seed)
+
+	settings := game.RoomSettings{TableSize: 4, TrumpSelection: game.TrumpSelectionAce, CardOrder: game.CardOrderAceHigh}
+	players := make([]*game.Player, settings.Seats())
+	for i := range players {
+		players[i] = &game.Player{ID: fmt.Sprint("p", i), Index: i, Team: game.TeamForSeat(i)}
+	}
+
+	_, deck, trumpPlayer, err := DealCards(ShuffleDeck(NewRoomDeck(settings)), players, nil, true, nil, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trumpPlayer.Index, trumpPlayer.Hand, deck
+}
+
+func TestFixedSeedGivesAFixedDeal(t *testing.T) {
+	defer func(d time.Duration) { CardDealDelay = d }(CardDealDelay)
+	CardDealDelay = 0
+
+	seat, hand, deck := seededDeal(t, 42)
+	if len(hand) != 5 || len(deck) != 52-5 {
+		t.Fatalf("Trump Player got %d cards with %d left in the deck, want 5 and 47", len(hand), len(deck))
+	}
+	for i := 0; i < 3; i++ {
+		againSeat, againHand, againDeck := seededDeal(t, 42)
+		if againSeat != seat || !reflect.DeepEqual(againHand, hand) || !reflect.DeepEqual(againDeck, deck) {
+			t.Fatalf("seed 42 dealt seat %d %v, then seat %d %v", seat, hand, againSeat, againHand)
+		}
+	}
+
+	if _, otherHand, otherDeck := seededDeal(t, 43); reflect.DeepEqual(otherHand, hand) && reflect.DeepEqual(otherDeck, deck) {
+		t.Fatal("seeds 42 and 43 dealt the same cards")
+	}
+}
+
+func TestDealCardsDealsTheDeckItIsGiven(t *testing.T) {
+	defer func(d time.Duration) { CardDealDelay = d }(CardDealDelay)
+	CardDealDelay = 0
+
+	settings := game.RoomSettings{TableSize: 4, TrumpSelection: game.TrumpSelectionAce, CardOrder: game.CardOrderAceHigh}
+	players := make([]*game.Player, settings.Seats())
+	for i := range players {
+		players[i] = &game.Player{ID: fmt.Sprint("p", i), Index: i, Team: game.TeamForSeat(i)}
+	}
+
+	// The Ace draw mustn't eat into the Round's deck
+	fixed := NewRoomDeck(settings)
+	_, deck, trumpPlayer, err := DealCards(fixed, players, nil, true, nil, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(trumpPlayer.Hand, fixed[:5]) || !reflect.DeepEqual(deck, fixed[5:]) {
+		t.Fatalf("Trump Player got %v, want the top of the deck %v", trumpPlayer.Hand, fixed[:5])
+	}
+}
+
+func TestChooseTrumpPlayerByEachMethod(t *testing.T) {
+	defer func(d time.Duration) { CardDealDelay = d }(CardDealDelay)
+	CardDealDelay = 0
+
+	deckOf := func(specs ...string) []game.Card {
+		var deck []game.Card
+		for _, spec := range specs {
+			var rank, suit string
+			fmt.Sscan(spec, &rank, &suit)
+			c, err := game.NewCard(suit, rank)
+			if err != nil {
+				t.Fatal(err)
+			}
+			deck = append(deck, c)
+		}
+		return deck
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		deck   []game.Card
+		want   int // Seat of the Trump Player, or -1 when the deck runs out
+		drawn  int // Cards the draw used up
+	}{
+		{"first Ace", game.TrumpSelectionAce, deckOf("2 hearts", "K spades", "A clubs", "A hearts"), 2, 3},
+		{"Ace after a full turn", game.TrumpSelectionAce, deckOf("2 hearts", "3 hearts", "4 hearts", "5 hearts", "A spades"), 0, 5},
+		{"no Ace", game.TrumpSelectionAce, deckOf("2 hearts", "3 hearts"), -1, 0},
+		{"highest card", game.TrumpSelectionHighest, deckOf("5 hearts", "Q spades", "K clubs", "2 hearts"), 2, 4},
+		{"tie drawn again", game.TrumpSelectionHighest, deckOf("K hearts", "2 spades", "K clubs", "3 hearts", "4 hearts", "9 clubs"), 2, 6},
+		{"tie until the deck runs out", game.TrumpSelectionHighest, deckOf("K hearts", "2 spades", "K clubs", "3 hearts", "5 hearts"), -1, 0},
+		{"fixed", game.TrumpSelectionFixed, deckOf("A hearts"), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := make([]*game.Player, 4)
+			for i := range players {
+				players[i] = &game.Player{ID: fmt.Sprint("p", i), Index: i}
+			}
+			if tt.method == game.TrumpSelectionFixed {
+				// The first seat, wherever it's listed
+				players[0], players[3] = players[3], players[0]
+			}
+
+			trumpPlayer, rest, err := chooseTrumpPlayer(tt.deck, players, nil, tt.method)
+			if tt.want < 0 {
+				if err == nil {
+					t.Fatalf("chooseTrumpPlayer() picked %v, want the draw to fail", trumpPlayer)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if trumpPlayer.Index != tt.want {
+				t.Fatalf("chooseTrumpPlayer() picked seat %d, want %d", trumpPlayer.Index, tt.want)
+			}
+			if len(rest) != len(tt.deck)-tt.drawn {
+				t.Errorf("%d cards left after the draw, want %d", len(rest), len(tt.deck)-tt.drawn)
+			}
+		})
+	}
+}
@@ -1,6 +1,45 @@
 package utils
 
+import "github.com/gin-gonic/gin"
+
 var (
 	ErrUserNotFound       = "user not found"
 	ErrInvalidCredentials = "invalid credentials"
 )
+
+// Error codes shared by REST responses and WebSocket "error" messages.
+// Clients switch on these, so they must stay stable.
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeWeakPassword       = "weak_password"
+	CodeMissingToken       = "missing_token"
+	CodeInvalidToken       = "invalid_token"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeRateLimited        = "rate_limited"
+	CodeUnavailable        = "unavailable"
+	CodeInternal           = "internal_error"
+	CodeInGame             = "in_game"
+
+	CodeInvalidCard    = "invalid_card"
+	CodeInvalidPlay    = "invalid_play"
+	CodeInvalidSuit    = "invalid_suit"
+	CodeNotTrumpPlayer = "not_trump_player"
+	CodeWrongPhase     = "wrong_phase"
+	CodeUndoRejected   = "undo_rejected"
+	CodeUnknownAction  = "unknown_action"
+	CodeAlreadyInGame  = "already_in_game"
+	CodeBadRequest     = "bad_request"
+)
+
+// APIError is the body of every error response
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RespondError aborts the request with the status and an {"error": APIError} body
+func RespondError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{"error": APIError{Code: code, Message: message}})
+}
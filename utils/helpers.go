@@ -4,125 +4,110 @@ import (
 	"fmt"
 	"hokm-backend/game"
 	"log"
-	"math/rand"
 	"time"
 )
 
+// CardDealDelay is the pause between single cards while choosing the Trump Player
+// and dealing their first cards. Set it to zero to deal instantly (e.g. in tests).
+var CardDealDelay = 250 * time.Millisecond
+
 func GenerateRoomID() string {
 	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	b := make([]byte, 6)
 	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+		b[i] = letters[game.Random.Intn(len(letters))]
 	}
 	return string(b)
 }
 
 // Initialize the deck with 52 cards
 func NewDeck() []game.Card {
-	suits := []string{"hearts", "diamonds", "clubs", "spades"}
-	ranks := []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "A"}
-	values := map[string]int{
-		"2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9, "10": 10,
-		"J": 11, "Q": 12, "K": 13, "A": 14,
+	return NewDeckVariant(game.DeckStandard, game.CardOrderAceHigh)
+}
+
+// NewDeckVariant builds the deck for a variant: 52 cards for "standard" and
+// 32 cards (2-6 removed) for "stripped", valued by the card order
+func NewDeckVariant(variant, order string) []game.Card {
+	ranks := game.Ranks
+	if variant == game.DeckStripped {
+		ranks = ranks[5:]
 	}
 
 	var deck []game.Card
-	for _, suit := range suits {
+	for _, suit := range game.Suits {
 		for _, rank := range ranks {
-			deck = append(deck, game.Card{
-				Suit:  suit,
-				Rank:  rank,
-				Value: values[rank],
-			})
+			card, err := game.NewOrderedCard(suit, rank, order)
+			if err != nil {
+				panic(err) // Suits and Ranks only hold valid cards
+			}
+			deck = append(deck, card)
 		}
 	}
 	return deck
 }
 
-// Shuffle the deck
+// Shuffle the deck using game.Random
 func ShuffleDeck(deck []game.Card) []game.Card {
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(deck), func(i, j int) {
+	game.Random.Shuffle(len(deck), func(i, j int) {
 		deck[i], deck[j] = deck[j], deck[i]
 	})
 	return deck
 }
 
-func DealCards(deck []game.Card, players []*game.Player, isInitialGame bool, trumpPlayer *game.Player) ([]*game.Player, []game.Card, *game.Player, error) {
-	// Step 0: Shuffle the deck
-	deck = ShuffleDeck(deck)
-	log.Println("Deck shuffled.")
-	log.Printf("Deck length after shuffling: %d\n", len(deck)) // Debug log
+// NewRoomDeck builds the deck of a room: its variant, without the 2s at a 6-player
+// table so the 48 cards split evenly
+func NewRoomDeck(settings game.RoomSettings) []game.Card {
+	deck := NewDeckVariant(settings.DeckVariant, settings.CardOrder)
+	if settings.Seats() != 6 {
+		return deck
+	}
 
-	// Step 1: Choose the Trump Player by dealing one card to each player until an Ace is drawn (only for initial game)
-	if isInitialGame {
-		log.Println("Choosing the Trump Player...")
-		for i := 0; ; i++ {
-			if len(deck) == 0 {
-				return nil, nil, nil, fmt.Errorf("not enough cards in the deck")
-			}
+	trimmed := deck[:0]
+	for _, c := range deck {
+		if c.Rank != "2" {
+			trimmed = append(trimmed, c)
+		}
+	}
+	return trimmed
+}
 
-			player := players[i%len(players)]
-			card := deck[0]
-			deck = deck[1:]
+// RoundDeck returns the deck a Round is dealt from, in dealing order. It's the one seam
+// for deterministic games: every Round deck comes from here, so tests can replace it with
+// a fixed deck, and together with game.Random = game.NewRNG(seed) (which decides the
+// Trump Player draw) and CardDealDelay = 0 a whole game can be played through
+// processMessage in a known order.
+var RoundDeck = func(settings game.RoomSettings) []game.Card {
+	return ShuffleDeck(NewRoomDeck(settings))
+}
 
-			// Log the card being dealt to the player
-			log.Printf("Dealt card %s of %s to %s\n", card.Rank, card.Suit, player.Name)
-
-			// Broadcast the card being dealt to all players
-			for _, p := range players {
-				p.Conn.WriteJSON(game.WSResponse{
-					Type: "dealing_card",
-					Payload: map[string]interface{}{
-						"player_id": player.ID,
-						"card":      card,
-					},
-				})
-			}
+// DealCards picks the Trump Player on the initial game by the room's TrumpSelection and
+// deals them the first batch of the room's deal pattern to choose trump from. deck is the
+// Round's deck from RoundDeck and is dealt in order; the draw for the Trump Player uses a
+// deck of its own. The observers see the draw along with the players.
+func DealCards(deck []game.Card, players []*game.Player, observers []*game.Observer, isInitialGame bool, trumpPlayer *game.Player, settings game.RoomSettings) ([]*game.Player, []game.Card, *game.Player, error) {
+	// Step 1: Choose the Trump Player the room's way (only for initial game)
+	if isInitialGame {
+		var err error
+		trumpPlayer, _, err = chooseTrumpPlayer(ShuffleDeck(NewRoomDeck(settings)), players, observers, settings.TrumpSelection)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 
-			// Add a delay of 1/4 second between each card deal
-			time.Sleep(250 * time.Millisecond)
-
-			// Add the card to the player's hand temporarily
-			player.Hand = append(player.Hand, card)
-
-			// Check if the card is an Ace
-			if card.Rank == "A" {
-				trumpPlayer = player
-				log.Printf("Trump Player chosen: %s (drew an Ace)\n", trumpPlayer.Name)
-
-				// Broadcast the Trump Player selection to all players
-				for _, p := range players {
-					p.Conn.WriteJSON(game.WSResponse{
-						Type: "trump_player_selected",
-						Payload: map[string]interface{}{
-							"trump_player_id": trumpPlayer.ID,
-							"card":            card,
-						},
-					})
-				}
-
-				// Clear the Trump Player's hand after selection
-				trumpPlayer.Hand = []game.Card{}
-				break
-			}
+		// Clear the drawn cards from every hand after selection
+		for _, p := range players {
+			p.Hand = []game.Card{}
 		}
 	} else {
 		// If not the initial game, use the existing Trump Player passed as an argument
 		log.Printf("Using existing Trump Player: %s\n", trumpPlayer.Name)
 	}
 
-	log.Printf("Deck length after choosing Trump Player: %d\n", len(deck)) // Debug log
+	log.Printf("Deck length before dealing: %d\n", len(deck)) // Debug log
 
-	// Step 2: Reset the deck to 52 cards and shuffle again
-	deck = NewDeck()
-	deck = ShuffleDeck(deck)
-	log.Println("Deck reset and shuffled again for dealing cards.")
-	log.Printf("Deck length after reshuffling: %d\n", len(deck)) // Debug log
-
-	// Step 3: Deal 5 cards to the Trump Player
-	log.Println("Dealing 5 cards to the Trump Player...")
-	for i := 0; i < 5; i++ {
+	// Step 2: Deal the first batch (5 cards by default) to the Trump Player
+	firstBatch := settings.DealBatches()[0]
+	log.Printf("Dealing %d cards to the Trump Player...", firstBatch)
+	for i := 0; i < firstBatch; i++ {
 		if len(deck) == 0 {
 			log.Println("Not enough cards in the deck")
 			return nil, nil, nil, fmt.Errorf("not enough cards in the deck")
@@ -131,12 +116,134 @@ func DealCards(deck []game.Card, players []*game.Player, isInitialGame bool, tru
 		deck = deck[1:]
 
 		// Add a delay of 1/4 second between each card deal
-		time.Sleep(250 * time.Millisecond)
+		time.Sleep(CardDealDelay)
 	}
 
-	log.Printf("Trump Player's hand after 5 cards: %v\n", trumpPlayer.Hand)
-	log.Printf("Deck length after dealing 5 cards to Trump Player: %d\n", len(deck)) // Debug log
+	log.Printf("Trump Player's hand after %d cards: %v\n", firstBatch, trumpPlayer.Hand)
+	log.Printf("Deck length after dealing %d cards to Trump Player: %d\n", firstBatch, len(deck)) // Debug log
 
 	// Return the players, deck, and Trump Player
 	return players, deck, trumpPlayer, nil
 }
+
+// chooseTrumpPlayer picks the first Trump Player of a match by the given method, drawing
+// from the top of deck, and returns them with what is left of the deck
+func chooseTrumpPlayer(deck []game.Card, players []*game.Player, observers []*game.Observer, method string) (*game.Player, []game.Card, error) {
+	log.Printf("Choosing the Trump Player (%s)...", method)
+	switch method {
+	case game.TrumpSelectionFixed:
+		trumpPlayer := players[0]
+		for _, p := range players {
+			if p.Index < trumpPlayer.Index {
+				trumpPlayer = p
+			}
+		}
+		announceTrumpPlayer(players, observers, trumpPlayer, nil, method)
+		return trumpPlayer, deck, nil
+	case game.TrumpSelectionHighest:
+		return drawHighestCard(deck, players, observers)
+	default:
+		return drawFirstAce(deck, players, observers)
+	}
+}
+
+// drawFirstAce deals one card to each player in turn until an Ace is drawn
+func drawFirstAce(deck []game.Card, players []*game.Player, observers []*game.Observer) (*game.Player, []game.Card, error) {
+	for i := 0; ; i++ {
+		if len(deck) == 0 {
+			return nil, nil, fmt.Errorf("not enough cards in the deck")
+		}
+
+		player := players[i%len(players)]
+		card := deck[0]
+		deck = deck[1:]
+		drawCard(players, observers, player, card)
+
+		if card.Rank == "A" {
+			log.Printf("Trump Player chosen: %s (drew an Ace)\n", player.Name)
+			announceTrumpPlayer(players, observers, player, &card, game.TrumpSelectionAce)
+			return player, deck, nil
+		}
+	}
+}
+
+// drawHighestCard deals everyone a card and picks whoever drew the highest. Players
+// tied for the highest card draw again among themselves until one is ahead; every
+// draw uses up cards, so it ends once a player is ahead or the deck runs out.
+func drawHighestCard(deck []game.Card, players []*game.Player, observers []*game.Observer) (*game.Player, []game.Card, error) {
+	contenders := players
+	for {
+		var best []*game.Player
+		var bestCard game.Card
+		for _, p := range contenders {
+			if len(deck) == 0 {
+				return nil, nil, fmt.Errorf("not enough cards in the deck")
+			}
+			card := deck[0]
+			deck = deck[1:]
+			drawCard(players, observers, p, card)
+
+			switch {
+			case len(best) == 0 || card.Value > bestCard.Value:
+				best = []*game.Player{p}
+				bestCard = card
+			case card.Value == bestCard.Value:
+				best = append(best, p)
+			}
+		}
+
+		if len(best) == 1 {
+			log.Printf("Trump Player chosen: %s (drew the highest card)\n", best[0].Name)
+			announceTrumpPlayer(players, observers, best[0], &bestCard, game.TrumpSelectionHighest)
+			return best[0], deck, nil
+		}
+		log.Printf("%d players tied with %s, drawing again", len(best), bestCard.Rank)
+		contenders = best
+	}
+}
+
+// drawCard shows everyone the card drawn by player and puts it in their hand for now.
+// The draw is face up, so observers see the card too.
+func drawCard(players []*game.Player, observers []*game.Observer, player *game.Player, card game.Card) {
+	// Log the card being dealt to the player
+	log.Printf("Dealt card %s of %s to %s\n", card.Rank, card.Suit, player.Name)
+
+	// Broadcast the card being dealt to all players and observers
+	resp := game.WSResponse{
+		Type: "dealing_card",
+		Payload: map[string]interface{}{
+			"player_id": player.ID,
+			"card":      card,
+		},
+	}
+	for _, p := range players {
+		p.Send(resp)
+	}
+	for _, o := range observers {
+		o.Conn.WriteJSON(resp)
+	}
+
+	// Add a delay of 1/4 second between each card deal
+	time.Sleep(CardDealDelay)
+
+	player.Hand = append(player.Hand, card)
+}
+
+// announceTrumpPlayer tells everyone who the Trump Player is, how they were picked and
+// with which card (none for the fixed method)
+func announceTrumpPlayer(players []*game.Player, observers []*game.Observer, trumpPlayer *game.Player, card *game.Card, method string) {
+	resp := game.WSResponse{
+		Type: "trump_player_selected",
+		Payload: map[string]interface{}{
+			"trump_player_id": trumpPlayer.ID,
+			"card":            card,
+			"method":          method,
+		},
+	}
+	for _, p := range players {
+		p.Send(resp)
+	}
+	for _, o := range observers {
+		o.Conn.WriteJSON(resp)
+	}
+}
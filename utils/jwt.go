@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"errors"
+	"hokm-backend/config"
+	"hokm-backend/models"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenTTL is how long an issued token stays valid
+const TokenTTL = 24 * time.Hour
+
+// Claims are the JWT claims issued on login
+type Claims struct {
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	TokenVersion int    `json:"token_version"`
+	Role         string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// ErrNoJWTSecret means JWT_SECRET isn't set, so tokens can't be signed or checked
+var ErrNoJWTSecret = errors.New("JWT_SECRET is not set")
+
+// CheckJWTSecret fails when JWT_SECRET is unset, so the server refuses to start
+// rather than sign tokens with a secret anyone can guess
+func CheckJWTSecret() error {
+	if len(jwtSecret()) == 0 {
+		return ErrNoJWTSecret
+	}
+	return nil
+}
+
+func jwtSecret() []byte {
+	return []byte(config.GetEnv("JWT_SECRET", ""))
+}
+
+// signClaims signs the claims with JWT_SECRET
+func signClaims(claims Claims) (string, error) {
+	secret := jwtSecret()
+	if len(secret) == 0 {
+		return "", ErrNoJWTSecret
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+// GenerateToken issues a signed token for the user
+func GenerateToken(userID, username, role string, tokenVersion int) (string, error) {
+	claims := Claims{
+		UserID:       userID,
+		Username:     username,
+		TokenVersion: tokenVersion,
+		Role:         role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	return signClaims(claims)
+}
+
+// GenerateGuestToken issues a token with the guest role for a player without an account
+func GenerateGuestToken(guestID, name string, ttl time.Duration) (string, error) {
+	claims := Claims{
+		UserID:   guestID,
+		Username: name,
+		Role:     models.RoleGuest,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	return signClaims(claims)
+}
+
+// ParseToken validates a signed token and returns its claims
+func ParseToken(tokenString string) (*Claims, error) {
+	secret := jwtSecret()
+	if len(secret) == 0 {
+		return nil, ErrNoJWTSecret
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return secret, nil
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
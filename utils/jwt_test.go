@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestTokenRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token, err := GenerateToken("42", "alice", "admin", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ParseToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != "42" || claims.Username != "alice" || claims.Role != "admin" || claims.TokenVersion != 3 {
+		t.Fatalf("ParseToken() = %+v, want the claims the token was issued with", claims)
+	}
+}
+
+func TestTokensNeedJWTSecret(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+
+	if err := CheckJWTSecret(); !errors.Is(err, ErrNoJWTSecret) {
+		t.Fatalf("CheckJWTSecret() = %v, want ErrNoJWTSecret", err)
+	}
+	if _, err := GenerateToken("42", "alice", "user", 0); !errors.Is(err, ErrNoJWTSecret) {
+		t.Fatalf("GenerateToken() error = %v, want ErrNoJWTSecret", err)
+	}
+	if _, err := ParseToken("anything"); !errors.Is(err, ErrNoJWTSecret) {
+		t.Fatalf("ParseToken() error = %v, want ErrNoJWTSecret", err)
+	}
+}
+
+func TestParseTokenRejectsExpiredAndForeignTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	expired, err := GenerateGuestToken("g1", "SwiftOtter", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseToken(expired); err == nil {
+		t.Error("an expired token was accepted")
+	}
+
+	otherSecret, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: "42"}).SignedString([]byte("other-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseToken(otherSecret); err == nil {
+		t.Error("a token signed with another secret was accepted")
+	}
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{UserID: "42"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseToken(unsigned); err == nil {
+		t.Error("an unsigned token was accepted")
+	}
+}